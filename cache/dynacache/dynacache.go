@@ -530,6 +530,12 @@ func (p *Partition[K, V]) getOptions() OptionsPartition {
 	return p.opts
 }
 
+// Delete deletes the value for the given key, if found.
+// It returns whether the key was found.
+func (p *Partition[K, V]) Delete(key K) bool {
+	return p.c.Delete(key)
+}
+
 func (p *Partition[K, V]) Clear() {
 	p.c.DeleteFunc(func(key K, v V) bool {
 		return true
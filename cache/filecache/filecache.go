@@ -141,6 +141,25 @@ func (c *Cache) WriteCloser(id string) (ItemInfo, io.WriteCloser, error) {
 	}, nil
 }
 
+// Delete removes the file with the given id from the cache, if found.
+// A missing file is not an error.
+func (c *Cache) Delete(id string) error {
+	if err := c.init(); err != nil {
+		return err
+	}
+
+	id = cleanID(id)
+
+	c.nlocker.Lock(id)
+	defer c.nlocker.Unlock(id)
+
+	err := c.Fs.Remove(id)
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
 // ReadOrCreate tries to lookup the file in cache.
 // If found, it is passed to read and then closed.
 // If not found a new file is created and passed to create, which should close
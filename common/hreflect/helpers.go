@@ -171,6 +171,40 @@ func GetMethodIndexByName(tp reflect.Type, name string) int {
 	return m.Index
 }
 
+type fields struct {
+	sync.RWMutex
+	cache map[methodKey]*reflect.StructField
+}
+
+var fieldCache = &fields{cache: make(map[methodKey]*reflect.StructField)}
+
+// GetStructFieldByName is the same as reflect.Type.FieldByName, but it caches
+// the lookup, including misses. tp must be a struct type.
+func GetStructFieldByName(tp reflect.Type, name string) (reflect.StructField, bool) {
+	k := methodKey{tp, name}
+	fieldCache.RLock()
+	f, found := fieldCache.cache[k]
+	fieldCache.RUnlock()
+	if found {
+		if f == nil {
+			return reflect.StructField{}, false
+		}
+		return *f, true
+	}
+
+	fieldCache.Lock()
+	defer fieldCache.Unlock()
+
+	ft, ok := tp.FieldByName(name)
+	if !ok {
+		fieldCache.cache[k] = nil
+		return reflect.StructField{}, false
+	}
+	fieldCache.cache[k] = &ft
+
+	return ft, true
+}
+
 var (
 	timeType           = reflect.TypeOf((*time.Time)(nil)).Elem()
 	asTimeProviderType = reflect.TypeOf((*htime.AsTimeProvider)(nil)).Elem()
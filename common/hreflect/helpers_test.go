@@ -41,6 +41,32 @@ func TestGetMethodByName(t *testing.T) {
 	c.Assert(GetMethodIndexByName(tp, "Foo"), qt.Equals, -1)
 }
 
+func TestGetStructFieldByName(t *testing.T) {
+	c := qt.New(t)
+
+	type embedded struct {
+		E string
+	}
+	type s struct {
+		embedded
+		A string
+	}
+	tp := reflect.TypeOf(s{})
+
+	for i := 0; i < 2; i++ {
+		ft, ok := GetStructFieldByName(tp, "A")
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(ft.Index, qt.DeepEquals, []int{1})
+
+		ft, ok = GetStructFieldByName(tp, "E")
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(ft.Index, qt.DeepEquals, []int{0, 0})
+
+		_, ok = GetStructFieldByName(tp, "Missing")
+		c.Assert(ok, qt.IsFalse)
+	}
+}
+
 func TestIsContextType(t *testing.T) {
 	c := qt.New(t)
 	type k string
@@ -587,6 +587,53 @@ type RootConfig struct {
 	// The length of text in words to show in a .Summary.
 	SummaryLength int
 
+	// The summary strategy to use for automatic summaries: "sentences"
+	// (default), "words" or "firstparagraph".
+	SummaryStrategy string
+
+	// Whether to keep paired delimiters (parentheses, brackets and double
+	// quotes) balanced when truncating the summary, backing off or extending
+	// the truncation point as needed. Default is false, which truncates on
+	// sentence boundaries only.
+	SummaryBalanceDelimiters bool
+
+	// The maximum size in bytes of the rendered content of a single page.
+	// When exceeded, the output is truncated and the render fails with an
+	// error. This guards against pathological input that explodes during
+	// rendering. Default is 0, which means no limit.
+	MaxRenderedContentSize int
+
+	// The admonition types, e.g. "note" and "warning", that the standalone
+	// content renderer rewrites from blockquote form (> [!NOTE]) into a
+	// styleable div. Blockquotes with unrecognized types are left as is.
+	// Default is empty, which disables the rewrite.
+	AdmonitionTypes []string
+
+	// The CSS class prefix used for rewritten admonitions, e.g.
+	// <div class="admonition note">. Default is "admonition".
+	AdmonitionClassPrefix string
+
+	// Whether the standalone content renderer wraps each rendered table in
+	// a scroll container div, so wide tables can overflow horizontally
+	// instead of breaking the layout on small screens. Default is false.
+	WrapTables bool
+
+	// The CSS class of the div that tables are wrapped in when WrapTables
+	// is enabled. Default is "table-responsive".
+	TableWrapperClass string
+
+	// Whether the standalone content renderer minifies the rendered HTML
+	// before returning it, using the settings from the minify section.
+	// Default is false.
+	MinifyRenderedContent bool
+
+	// A map of fenced code block language hints to the canonical name the
+	// syntax highlighter should see, e.g. "js" to "javascript". Merged with
+	// a built-in set of common aliases, with user entries winning; an entry
+	// with an empty value disables the built-in alias for that key.
+	// Languages without an alias pass through unchanged.
+	CodeBlockLanguageAliases map[string]string
+
 	// The site title.
 	Title string
 
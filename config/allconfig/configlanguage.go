@@ -207,6 +207,42 @@ func (c ConfigLanguage) SummaryLength() int {
 	return c.config.SummaryLength
 }
 
+func (c ConfigLanguage) SummaryStrategy() string {
+	return c.config.SummaryStrategy
+}
+
+func (c ConfigLanguage) SummaryBalanceDelimiters() bool {
+	return c.config.SummaryBalanceDelimiters
+}
+
+func (c ConfigLanguage) MaxRenderedContentSize() int {
+	return c.config.MaxRenderedContentSize
+}
+
+func (c ConfigLanguage) AdmonitionTypes() []string {
+	return c.config.AdmonitionTypes
+}
+
+func (c ConfigLanguage) AdmonitionClassPrefix() string {
+	return c.config.AdmonitionClassPrefix
+}
+
+func (c ConfigLanguage) WrapTables() bool {
+	return c.config.WrapTables
+}
+
+func (c ConfigLanguage) TableWrapperClass() string {
+	return c.config.TableWrapperClass
+}
+
+func (c ConfigLanguage) MinifyRenderedContent() bool {
+	return c.config.MinifyRenderedContent
+}
+
+func (c ConfigLanguage) CodeBlockLanguageAliases() map[string]string {
+	return c.config.CodeBlockLanguageAliases
+}
+
 func (c ConfigLanguage) BuildExpired() bool {
 	return c.config.BuildExpired
 }
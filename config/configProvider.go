@@ -51,6 +51,15 @@ type AllProvider interface {
 	DefaultContentLanguageInSubdir() bool
 	IsLangDisabled(string) bool
 	SummaryLength() int
+	SummaryStrategy() string
+	SummaryBalanceDelimiters() bool
+	MaxRenderedContentSize() int
+	AdmonitionTypes() []string
+	AdmonitionClassPrefix() string
+	WrapTables() bool
+	TableWrapperClass() string
+	MinifyRenderedContent() bool
+	CodeBlockLanguageAliases() map[string]string
 	Paginate() int
 	PaginatePath() string
 	BuildExpired() bool
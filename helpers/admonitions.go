@@ -0,0 +1,131 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// admonitionMarkerRe matches an admonition marker, e.g. [!NOTE], at the
+// start of a rendered blockquote. The first group is the markup to keep,
+// the second the admonition type.
+var admonitionMarkerRe = regexp.MustCompile(`^(\s*<p>)\[!([\w-]+)\][^\S\n]*\n?`)
+
+// applyAdmonitions rewrites blockquotes that start with a recognized
+// admonition marker, e.g. the rendering of
+//
+//	> [!NOTE]
+//	> Remember this.
+//
+// into a styleable div:
+//
+//	<div class="admonition note"><p>Remember this.</p></div>
+//
+// The recognized types and the class prefix are configured with
+// admonitionTypes and admonitionClassPrefix; with no types configured,
+// content passes through unchanged, as do blockquotes with unrecognized
+// types.
+func (c *ContentSpec) applyAdmonitions(content []byte) []byte {
+	types := c.Cfg.AdmonitionTypes()
+	if len(types) == 0 || !bytes.Contains(content, []byte("[!")) {
+		return content
+	}
+
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[strings.ToLower(t)] = true
+	}
+
+	classPrefix := c.Cfg.AdmonitionClassPrefix()
+	if classPrefix == "" {
+		classPrefix = "admonition"
+	}
+
+	return rewriteAdmonitions(content, allowed, classPrefix)
+}
+
+const (
+	blockquoteOpen  = "<blockquote>"
+	blockquoteClose = "</blockquote>"
+)
+
+func rewriteAdmonitions(b []byte, allowed map[string]bool, classPrefix string) []byte {
+	var buf bytes.Buffer
+
+	for {
+		i := bytes.Index(b, []byte(blockquoteOpen))
+		if i == -1 {
+			break
+		}
+		rest := b[i+len(blockquoteOpen):]
+
+		m := admonitionMarkerRe.FindSubmatch(rest)
+		var name string
+		if m != nil {
+			name = strings.ToLower(string(m[2]))
+		}
+		if m == nil || !allowed[name] {
+			// Not an admonition, or an unrecognized type; leave the
+			// blockquote as is.
+			buf.Write(b[:i+len(blockquoteOpen)])
+			b = rest
+			continue
+		}
+
+		inner := rest[len(m[0]):]
+		end, ok := findBlockquoteEnd(inner)
+		if !ok {
+			// Unbalanced markup; leave it alone.
+			buf.Write(b[:i+len(blockquoteOpen)])
+			b = rest
+			continue
+		}
+
+		buf.Write(b[:i])
+		buf.WriteString(`<div class="` + classPrefix + ` ` + name + `">`)
+		buf.Write(m[1])
+		// The admonition may itself contain admonitions.
+		buf.Write(rewriteAdmonitions(inner[:end], allowed, classPrefix))
+		buf.WriteString("</div>")
+		b = inner[end+len(blockquoteClose):]
+	}
+
+	buf.Write(b)
+	return buf.Bytes()
+}
+
+// findBlockquoteEnd returns the offset of the </blockquote> that closes the
+// blockquote b starts inside of, taking nested blockquotes into account.
+func findBlockquoteEnd(b []byte) (int, bool) {
+	var off, depth int
+	for {
+		no := bytes.Index(b[off:], []byte(blockquoteOpen))
+		nc := bytes.Index(b[off:], []byte(blockquoteClose))
+		if nc == -1 {
+			return 0, false
+		}
+		if no != -1 && no < nc {
+			depth++
+			off += no + len(blockquoteOpen)
+			continue
+		}
+		if depth == 0 {
+			return off + nc, true
+		}
+		depth--
+		off += nc + len(blockquoteClose)
+	}
+}
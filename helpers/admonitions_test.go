@@ -0,0 +1,63 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestRenderBytesAdmonitions(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	cfg.Set("admonitionTypes", []string{"note", "warning"})
+	spec := newTestContentSpec(cfg)
+
+	for _, test := range []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"simple", "> [!NOTE]\n> Remember this.\n",
+			"<div class=\"admonition note\">\n<p>Remember this.</p>\n</div>\n"},
+		{"nested content", "> [!WARNING]\n> Careful.\n>\n> > A quote inside.\n",
+			"<div class=\"admonition warning\">\n<p>Careful.</p>\n<blockquote>\n<p>A quote inside.</p>\n</blockquote>\n</div>\n"},
+		{"unrecognized type", "> [!TIP]\n> Try this.\n",
+			"<blockquote>\n<p>[!TIP]\nTry this.</p>\n</blockquote>\n"},
+		{"plain blockquote", "> Just a quote.\n",
+			"<blockquote>\n<p>Just a quote.</p>\n</blockquote>\n"},
+	} {
+		b, err := spec.RenderBytes("markdown", []byte(test.content))
+		c.Assert(err, qt.IsNil, qt.Commentf(test.name))
+		c.Assert(string(b), qt.Equals, test.expected, qt.Commentf(test.name))
+	}
+
+	// Disabled unless types are configured.
+	spec = newTestContentSpec(config.New())
+	b, err := spec.RenderBytes("markdown", []byte("> [!NOTE]\n> Remember this.\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "<blockquote>\n<p>[!NOTE]\nRemember this.</p>\n</blockquote>\n")
+
+	// The class prefix is configurable.
+	cfg = config.New()
+	cfg.Set("admonitionTypes", []string{"note"})
+	cfg.Set("admonitionClassPrefix", "callout")
+	spec = newTestContentSpec(cfg)
+	b, err = spec.RenderBytes("markdown", []byte("> [!NOTE]\n> Remember this.\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "<div class=\"callout note\">\n<p>Remember this.</p>\n</div>\n")
+}
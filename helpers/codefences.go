@@ -0,0 +1,99 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"strings"
+)
+
+// DefaultCodeBlockLanguageAliases maps common fenced code block language
+// hints to the canonical name the syntax highlighter should see. User
+// entries in codeBlockLanguageAliases are merged on top; an entry with an
+// empty value disables the built-in alias for that key.
+var DefaultCodeBlockLanguageAliases = map[string]string{
+	"c++":    "cpp",
+	"golang": "go",
+	"js":     "javascript",
+	"shell":  "bash",
+	"ts":     "typescript",
+	"yml":    "yaml",
+}
+
+// normalizeCodeFenceLanguages rewrites the language hint on fenced code
+// blocks (``` or ~~~) in src to its canonical name, e.g. js to javascript,
+// so inconsistently specified languages all hit the same highlighter lexer.
+// The aliases are DefaultCodeBlockLanguageAliases extended and overridden by
+// codeBlockLanguageAliases; languages without an alias pass through
+// unchanged, as does any attribute block following the hint. Fence lines
+// inside an open fence are code content and left alone.
+func (c *ContentSpec) normalizeCodeFenceLanguages(src []byte) []byte {
+	aliases := make(map[string]string, len(DefaultCodeBlockLanguageAliases))
+	for k, v := range DefaultCodeBlockLanguageAliases {
+		aliases[k] = v
+	}
+	for k, v := range c.Cfg.CodeBlockLanguageAliases() {
+		aliases[strings.ToLower(k)] = v
+	}
+
+	lines := strings.Split(string(src), "\n")
+	var (
+		inFence   bool
+		fenceChar byte
+		fenceLen  int
+		changed   bool
+	)
+
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		if inFence {
+			if indent <= 3 && fenceRun(trimmed, fenceChar) >= fenceLen && strings.TrimRight(trimmed, string(fenceChar)+" ") == "" {
+				inFence = false
+			}
+			continue
+		}
+
+		if indent > 3 || len(trimmed) == 0 || (trimmed[0] != '`' && trimmed[0] != '~') {
+			continue
+		}
+		n := fenceRun(trimmed, trimmed[0])
+		if n < 3 {
+			continue
+		}
+		inFence, fenceChar, fenceLen = true, trimmed[0], n
+
+		// The info string, i.e. what follows the fence run; its first
+		// word is the language hint.
+		rest := strings.TrimLeft(trimmed[n:], " ")
+		lang := rest
+		if j := strings.IndexAny(rest, " \t{"); j != -1 {
+			lang = rest[:j]
+		}
+		if lang == "" {
+			continue
+		}
+		canonical, found := aliases[strings.ToLower(lang)]
+		if !found || canonical == "" || canonical == lang {
+			continue
+		}
+		lines[i] = line[:len(line)-len(rest)] + canonical + rest[len(lang):]
+		changed = true
+	}
+
+	if !changed {
+		return src
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestRenderBytesCodeFenceLanguageAliases(t *testing.T) {
+	c := qt.New(t)
+
+	spec := newTestContentSpec(config.New())
+
+	// Built-in aliases.
+	b, err := spec.RenderBytes("markdown", []byte("```js\nvar a = 1;\n```\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, `data-lang="javascript"`)
+	c.Assert(string(b), qt.Not(qt.Contains), `data-lang="js"`)
+
+	b, err = spec.RenderBytes("markdown", []byte("```c++\nint a = 1;\n```\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, `data-lang="cpp"`)
+
+	// Tilde fences work too.
+	b, err = spec.RenderBytes("markdown", []byte("~~~golang\na := 1\n~~~\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, `data-lang="go"`)
+
+	// Unknown languages pass through unchanged.
+	b, err = spec.RenderBytes("markdown", []byte("```frobnicate\nfrob\n```\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, `data-lang="frobnicate"`)
+
+	// Fence lines inside an open fence are code content, not fences.
+	b, err = spec.RenderBytes("markdown", []byte("~~~~\n```js\nvar a = 1;\n```\n~~~~\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, "```js")
+	c.Assert(string(b), qt.Not(qt.Contains), "javascript")
+
+	// The alias map is user-extensible, and an empty value disables a
+	// built-in alias.
+	cfg := config.New()
+	cfg.Set("codeBlockLanguageAliases", map[string]string{
+		"frobnicate": "lua",
+		"js":         "",
+	})
+	spec = newTestContentSpec(cfg)
+
+	b, err = spec.RenderBytes("markdown", []byte("```frobnicate\nfrob\n```\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, `data-lang="lua"`)
+
+	b, err = spec.RenderBytes("markdown", []byte("```js\nvar a = 1;\n```\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, `data-lang="js"`)
+}
@@ -19,7 +19,14 @@ package helpers
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"html/template"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -28,18 +35,24 @@ import (
 	"github.com/gohugoio/hugo/common/loggers"
 
 	"github.com/spf13/afero"
+	"golang.org/x/net/html"
 
 	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/converter/hooks"
 
 	"github.com/gohugoio/hugo/markup"
 
 	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/minifiers"
+	"github.com/gohugoio/hugo/output"
 )
 
 // ContentSpec provides functionality to render markdown content.
 type ContentSpec struct {
 	Converters          markup.ConverterProvider
 	anchorNameSanitizer converter.AnchorNameSanitizer
+	minifier            *minifiers.Client
 	Cfg                 config.AllProvider
 }
 
@@ -78,6 +91,14 @@ func NewContentSpec(cfg config.AllProvider, logger loggers.Logger, contentFs afe
 		spec.anchorNameSanitizer = conv.(converter.AnchorNameSanitizer)
 	}
 
+	if cfg.MinifyRenderedContent() {
+		m, err := minifiers.New(media.DefaultTypes, output.DefaultFormats, cfg)
+		if err != nil {
+			return nil, err
+		}
+		spec.minifier = &m
+	}
+
 	return spec, nil
 }
 
@@ -152,6 +173,895 @@ func (c *ContentSpec) ResolveMarkup(in string) string {
 	return ""
 }
 
+// DetectFormat detects the markup format of the named file, first from its
+// extension and, failing that, by sniffing the content for format specific
+// syntax such as reST directives or an AsciiDoc document title. It returns
+// a markup identifier accepted by ResolveMarkup and RenderBytes, defaulting
+// to "markdown".
+func DetectFormat(filename string, content []byte) string {
+	switch strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".") {
+	case "md", "markdown", "mdown":
+		return "markdown"
+	case "ad", "adoc", "asciidoc":
+		return "asciidoc"
+	case "rst":
+		return "rst"
+	case "pdc", "pandoc":
+		return "pandoc"
+	case "org":
+		return "org"
+	case "html", "htm":
+		return "html"
+	}
+
+	// No (known) extension; sniff the content.
+	if m := firstNonBlankLine(content); m != nil {
+		if bytes.HasPrefix(m, []byte("= ")) {
+			// An AsciiDoc document title.
+			return "asciidoc"
+		}
+		if bytes.HasPrefix(m, []byte("% ")) {
+			// A Pandoc title block.
+			return "pandoc"
+		}
+	}
+	if restDirectiveRe.Match(content) {
+		return "rst"
+	}
+
+	return "markdown"
+}
+
+// restDirectiveRe matches a reST directive, e.g. ".. note::".
+var restDirectiveRe = regexp.MustCompile(`(?m)^\.\. [\w-]+::`)
+
+// firstNonBlankLine returns the first line in content with any non-space
+// characters, or nil.
+func firstNonBlankLine(content []byte) []byte {
+	for len(content) > 0 {
+		line := content
+		if i := bytes.IndexByte(content, '\n'); i != -1 {
+			line, content = content[:i], content[i+1:]
+		} else {
+			content = nil
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			return line
+		}
+	}
+	return nil
+}
+
+// CountElements parses content as HTML and counts the number of image,
+// link and heading (h1-h6) elements. Anchor-only links (href="#foo") are
+// not counted as links. The parse is tolerant, so malformed HTML counts
+// whatever elements can be recognized.
+func CountElements(content []byte) (images, links, headings int) {
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		switch string(name) {
+		case "img":
+			images++
+		case "a":
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if string(key) == "href" {
+					if !strings.HasPrefix(string(val), "#") {
+						links++
+					}
+					break
+				}
+			}
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			headings++
+		}
+	}
+}
+
+// RenderPlain converts src, written in the given markup, to plain text in
+// one step: it renders through the resolved converter and then strips the
+// resulting HTML, collapsing whitespace and decoding entities. Useful for
+// e.g. meta descriptions where neither markup nor HTML is wanted. For
+// "html" the render step is skipped. Returns an error for unknown markup.
+func (c *ContentSpec) RenderPlain(markup string, src []byte) (string, error) {
+	resolved := c.ResolveMarkup(markup)
+	if resolved == "" {
+		return "", fmt.Errorf("unknown markup %q", markup)
+	}
+
+	b := src
+	if resolved != "html" {
+		p := c.Converters.Get(resolved)
+		conv, err := p.New(converter.DocumentContext{})
+		if err != nil {
+			return "", err
+		}
+		r, err := conv.Convert(converter.RenderContext{
+			Ctx: context.Background(),
+			Src: src,
+			// No render hooks; the output gets stripped anyway.
+			GetRenderer: func(hooks.RendererType, any) any { return nil },
+		})
+		if err != nil {
+			return "", err
+		}
+		b = r.Bytes()
+	}
+
+	return extractPlainText(b), nil
+}
+
+// SplitByHeading splits content into chunks, each beginning at a heading
+// of the given level (1-6). Content before the first such heading forms the
+// first chunk; if the content starts with a matching heading, no leading
+// chunk is emitted. The split happens on the raw bytes, so the headings and
+// their IDs are preserved verbatim in each chunk. Useful for paginating
+// very long rendered articles on heading boundaries.
+func SplitByHeading(content []byte, level int) [][]byte {
+	tag := []byte(fmt.Sprintf("h%d", level))
+	var offsets []int
+	z := html.NewTokenizer(bytes.NewReader(content))
+	offset := 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := len(z.Raw())
+		if tt == html.StartTagToken {
+			name, _ := z.TagName()
+			if bytes.Equal(name, tag) {
+				offsets = append(offsets, offset)
+			}
+		}
+		offset += raw
+	}
+
+	chunks := make([][]byte, 0, len(offsets)+1)
+	prev := 0
+	for _, o := range offsets {
+		if o > prev || prev > 0 {
+			chunks = append(chunks, content[prev:o])
+		}
+		prev = o
+	}
+	chunks = append(chunks, content[prev:])
+	return chunks
+}
+
+// ErrRenderedContentTooLarge is wrapped in the error returned by RenderBytes
+// when the rendered output exceeds the configured maximum size.
+var ErrRenderedContentTooLarge = errors.New("rendered content size exceeds the configured maximum")
+
+// RenderBytes renders src, written in the given markup, to HTML using the
+// resolved converter. If maxRenderedContentSize is configured to a positive
+// value and the output is larger, the output is truncated to that size and
+// an error wrapping ErrRenderedContentTooLarge is returned. This guards
+// against pathological input (e.g. deeply nested lists) that blows up
+// during rendering. Returns an error for unknown markup.
+func (c *ContentSpec) RenderBytes(markup string, src []byte) ([]byte, error) {
+	resolved := c.ResolveMarkup(markup)
+	if resolved == "" {
+		return nil, fmt.Errorf("unknown markup %q", markup)
+	}
+
+	b := src
+	if resolved != "html" {
+		src = c.normalizeCodeFenceLanguages(src)
+		p := c.Converters.Get(resolved)
+		conv, err := p.New(converter.DocumentContext{})
+		if err != nil {
+			return nil, err
+		}
+		r, err := conv.Convert(converter.RenderContext{
+			Ctx: context.Background(),
+			Src: src,
+			// No user render hooks in this standalone helper, but code
+			// blocks need the native highlighter.
+			GetRenderer: func(tp hooks.RendererType, id any) any {
+				if tp == hooks.CodeBlockRendererType {
+					return c.Converters.GetHighlighter()
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		b = r.Bytes()
+	}
+
+	b = c.applyAdmonitions(b)
+	b = c.applyTableWrapper(b)
+
+	if c.minifier != nil {
+		var buf bytes.Buffer
+		if err := c.minifier.Minify(media.Builtin.HTMLType, &buf, bytes.NewReader(b)); err != nil {
+			return nil, err
+		}
+		b = buf.Bytes()
+	}
+
+	if max := c.Cfg.MaxRenderedContentSize(); max > 0 && len(b) > max {
+		return b[:max], fmt.Errorf("%w: got %d bytes, the limit is %d", ErrRenderedContentTooLarge, len(b), max)
+	}
+
+	return b, nil
+}
+
+// AddHeadingIDs returns content with an id attribute added to every heading
+// (h1-h6) that lacks one, so imported HTML works with TOCs and deep links.
+// The id is generated from the heading text using the same anchor
+// sanitization as for markdown rendering, with the given prefix prepended
+// and a numeric suffix appended for repeated headings. Headings with an
+// existing id are left untouched.
+func (c *ContentSpec) AddHeadingIDs(content []byte, prefix string) ([]byte, error) {
+	node, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && isHeadingElement(n.Data) {
+			var id string
+			for _, a := range n.Attr {
+				if a.Key == "id" {
+					id = a.Val
+					break
+				}
+			}
+			if id == "" {
+				base := prefix + c.SanitizeAnchorName(headingText(n))
+				id = base
+				for i := 1; seen[id]; i++ {
+					id = base + "-" + strconv.Itoa(i)
+				}
+				n.Attr = append(n.Attr, html.Attribute{Key: "id", Val: id})
+			}
+			seen[id] = true
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, node); err != nil {
+		return nil, err
+	}
+	// ltrim <html><head></head><body> and rtrim </body></html> which are
+	// added by html.Render.
+	b := buf.Bytes()[25:]
+	return b[:len(b)-14], nil
+}
+
+func isHeadingElement(name string) bool {
+	return len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6'
+}
+
+// headingText returns the plain text content of the given node.
+func headingText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// extractPlainText returns the text content of the given HTML with entities
+// decoded and whitespace collapsed to single spaces.
+func extractPlainText(content []byte) string {
+	var sb strings.Builder
+	z := html.NewTokenizer(bytes.NewReader(content))
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(sb.String()), " ")
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style":
+				skip++
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style":
+				if skip > 0 {
+					skip--
+				}
+			}
+		case html.TextToken:
+			if skip == 0 {
+				sb.Write(z.Text())
+			}
+		}
+	}
+}
+
+// MetaDescription returns a plain-text description of the rendered content
+// suitable for a meta description tag: tags are stripped, entities decoded
+// and whitespace collapsed, and the result is cut to at most maxChars runes
+// on a word boundary, with an ellipsis appended when truncated. Content
+// before a summary divider (<!--more-->), if present, is preferred.
+func MetaDescription(content []byte, maxChars int) string {
+	if i := bytes.Index(content, SummaryDivider); i != -1 {
+		content = content[:i]
+	}
+
+	s := extractPlainText(content)
+	if maxChars <= 0 || utf8.RuneCountInString(s) <= maxChars {
+		return s
+	}
+
+	var (
+		end      = len(s)
+		lastWord int
+		count    int
+	)
+	for i, r := range s {
+		if count == maxChars {
+			end = i
+			break
+		}
+		if unicode.IsSpace(r) {
+			lastWord = i
+		}
+		count++
+	}
+	if lastWord > 0 {
+		end = lastWord
+	}
+	return strings.TrimRightFunc(s[:end], unicode.IsSpace) + " …"
+}
+
+// Heading holds the level, anchor ID and stripped text of one heading
+// element, as returned by ExtractHeadings.
+type Heading struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+// ExtractHeadings parses content as HTML and returns all h1-h6 elements in
+// document order, e.g. for building a search index. Headings without an id
+// attribute get an empty ID.
+func ExtractHeadings(content []byte) []Heading {
+	var headings []Heading
+	z := html.NewTokenizer(bytes.NewReader(content))
+	var (
+		current *Heading
+		text    strings.Builder
+	)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return headings
+		}
+		switch tt {
+		case html.StartTagToken:
+			name, hasAttr := z.TagName()
+			if level := headingLevel(name); level > 0 {
+				current = &Heading{Level: level}
+				text.Reset()
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					if string(key) == "id" {
+						current.ID = string(val)
+						break
+					}
+				}
+			}
+		case html.TextToken:
+			if current != nil {
+				text.Write(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if current != nil && headingLevel(name) == current.Level {
+				current.Text = strings.Join(strings.Fields(text.String()), " ")
+				headings = append(headings, *current)
+				current = nil
+			}
+		}
+	}
+}
+
+// ValidateAnchors parses content as HTML and returns the href="#..."
+// targets that don't resolve to any element ID, in document order. External
+// and non-fragment links are ignored, and the parse tolerates malformed
+// HTML. Useful for linting dangling in-page anchors after rendering.
+func ValidateAnchors(content []byte) []string {
+	var (
+		ids     = map[string]bool{}
+		anchors []string
+	)
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		isAnchor := len(name) == 1 && name[0] == 'a'
+		for hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			switch string(key) {
+			case "id":
+				ids[string(val)] = true
+			case "href":
+				if isAnchor && len(val) > 1 && val[0] == '#' {
+					anchors = append(anchors, string(val[1:]))
+				}
+			}
+		}
+	}
+
+	var broken []string
+	for _, a := range anchors {
+		if !ids[a] {
+			broken = append(broken, a)
+		}
+	}
+	return broken
+}
+
+// DuplicateHeadingIDs parses content as HTML and returns the ids used by
+// more than one heading (h1-h6), in the order the first duplication appears.
+// Colliding ids break anchors, so this is useful for a lint step warning
+// authors about repeated headings; AddHeadingIDs de-duplicates the ids it
+// generates itself. The parse tolerates malformed HTML.
+func DuplicateHeadingIDs(content []byte) []string {
+	seen := map[string]int{}
+	var dups []string
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return dups
+		}
+		if tt != html.StartTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		if headingLevel(name) == 0 {
+			continue
+		}
+		for hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			if string(key) == "id" {
+				id := string(val)
+				seen[id]++
+				if seen[id] == 2 {
+					dups = append(dups, id)
+				}
+				break
+			}
+		}
+	}
+}
+
+// Figure holds the source, alt text and caption of one image, as returned
+// by ExtractFigures.
+type Figure struct {
+	Src     string
+	Alt     string
+	Caption string
+}
+
+// ExtractFigures parses content as HTML and returns every image in document
+// order, e.g. for building a list of figures. For an image wrapped in a
+// <figure> element the caption is the text of its <figcaption>, falling back
+// to the image's alt text; bare images get an empty caption.
+func ExtractFigures(content []byte) []Figure {
+	root, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	nodeAttr := func(n *html.Node, key string) string {
+		for _, a := range n.Attr {
+			if a.Key == key {
+				return a.Val
+			}
+		}
+		return ""
+	}
+
+	var figures []Figure
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "figure":
+				var (
+					img     *html.Node
+					caption string
+				)
+				var inner func(n *html.Node)
+				inner = func(n *html.Node) {
+					if n.Type == html.ElementNode {
+						switch n.Data {
+						case "img":
+							if img == nil {
+								img = n
+							}
+						case "figcaption":
+							if caption == "" {
+								caption = strings.Join(strings.Fields(headingText(n)), " ")
+							}
+						}
+					}
+					for c := n.FirstChild; c != nil; c = c.NextSibling {
+						inner(c)
+					}
+				}
+				inner(n)
+				if img != nil {
+					f := Figure{
+						Src:     nodeAttr(img, "src"),
+						Alt:     nodeAttr(img, "alt"),
+						Caption: caption,
+					}
+					if f.Caption == "" {
+						f.Caption = f.Alt
+					}
+					figures = append(figures, f)
+				}
+				// The wrapped image is accounted for; don't descend.
+				return
+			case "img":
+				figures = append(figures, Figure{
+					Src: nodeAttr(n, "src"),
+					Alt: nodeAttr(n, "alt"),
+				})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return figures
+}
+
+// headingLevel returns the level of a h1-h6 tag name, or 0 for other tags.
+func headingLevel(name []byte) int {
+	if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+	return 0
+}
+
+// FirstParagraph parses content as HTML and returns the first <p> element
+// with any text in it, including its tags. Empty paragraphs and paragraphs
+// containing only images are skipped. It returns nil if no such paragraph is
+// found.
+func FirstParagraph(content []byte) []byte {
+	root, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	var hasText func(n *html.Node) bool
+	hasText = func(n *html.Node) bool {
+		if n.Type == html.TextNode && strings.TrimSpace(n.Data) != "" {
+			return true
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if hasText(c) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var find func(n *html.Node) *html.Node
+	find = func(n *html.Node) *html.Node {
+		if n.Type == html.ElementNode && n.Data == "p" {
+			if hasText(n) {
+				return n
+			}
+			return nil
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if p := find(c); p != nil {
+				return p
+			}
+		}
+		return nil
+	}
+
+	p := find(root)
+	if p == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, p); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// NormalizeHeadingAnchor returns a GitHub style (lowercase, dashed) anchor
+// for s: spaces become dashes and any rune that is not a letter, digit, dash
+// or underscore is dropped.
+func NormalizeHeadingAnchor(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+var (
+	headingIDRe  = regexp.MustCompile(`(<h[1-6][^>]*\sid=")([^"]*)(")`)
+	anchorHrefRe = regexp.MustCompile(`(href="#)([^"]*)(")`)
+	imgSrcRe     = regexp.MustCompile(`(<img[^>]*\ssrc=")([^"]*)(")`)
+)
+
+// RewriteImages rewrites the src of every <img> element with a relative URL
+// in content through resolve, so raw image references can be routed through
+// e.g. the image processing pipeline. Absolute and remote URLs (scheme,
+// protocol relative or data URIs) are passed through unchanged, as are
+// sources where resolve returns an error.
+func RewriteImages(content []byte, resolve func(src string) (string, error)) []byte {
+	return imgSrcRe.ReplaceAllFunc(content, func(m []byte) []byte {
+		parts := imgSrcRe.FindSubmatch(m)
+		src := string(parts[2])
+
+		if src == "" || strings.HasPrefix(src, "//") || strings.Contains(src, ":") {
+			// Absolute, protocol relative or data URI.
+			return m
+		}
+
+		resolved, err := resolve(src)
+		if err != nil {
+			return m
+		}
+		return append(append(parts[1], resolved...), parts[3]...)
+	})
+}
+
+var aHrefRe = regexp.MustCompile(`(<a[^>]*\shref=")([^"]*)(")`)
+
+// DefaultTrackingParams is the set of query parameters StripTrackingParams
+// removes when given no explicit list. Entries ending in "*" match by
+// prefix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"mc_eid",
+	"igshid",
+	"twclid",
+}
+
+// StripTrackingParams removes the given tracking query parameters from
+// every <a> href in content, re-encoding the remaining query. Entries
+// ending in "*" match by prefix; a nil params uses DefaultTrackingParams.
+// Hrefs without a query, fragment-only links and unparsable URLs are left
+// untouched.
+func StripTrackingParams(content []byte, params []string) []byte {
+	if params == nil {
+		params = DefaultTrackingParams
+	}
+
+	isTracking := func(name string) bool {
+		for _, p := range params {
+			if strings.HasSuffix(p, "*") {
+				if strings.HasPrefix(name, p[:len(p)-1]) {
+					return true
+				}
+			} else if name == p {
+				return true
+			}
+		}
+		return false
+	}
+
+	return aHrefRe.ReplaceAllFunc(content, func(m []byte) []byte {
+		parts := aHrefRe.FindSubmatch(m)
+		href := string(parts[2])
+
+		if !strings.Contains(href, "?") {
+			return m
+		}
+
+		u, err := url.Parse(href)
+		if err != nil {
+			return m
+		}
+
+		q := u.Query()
+		var removed bool
+		for name := range q {
+			if isTracking(name) {
+				q.Del(name)
+				removed = true
+			}
+		}
+		if !removed {
+			return m
+		}
+		u.RawQuery = q.Encode()
+		return append(append(parts[1], u.String()...), parts[3]...)
+	})
+}
+
+// NormalizeHeadingAnchors rewrites the id attributes of h1-h6 elements in
+// content through NormalizeHeadingAnchor, keeping any fragment links
+// pointing at the rewritten IDs in sync. Different markup engines slugify
+// headings differently; this makes them consistent across formats.
+func NormalizeHeadingAnchors(content []byte) []byte {
+	rewritten := make(map[string]string)
+
+	content = headingIDRe.ReplaceAllFunc(content, func(m []byte) []byte {
+		parts := headingIDRe.FindSubmatch(m)
+		id := string(parts[2])
+		normalized := NormalizeHeadingAnchor(id)
+		if normalized != id {
+			rewritten[id] = normalized
+		}
+		return append(append(parts[1], normalized...), parts[3]...)
+	})
+
+	if len(rewritten) == 0 {
+		return content
+	}
+
+	return anchorHrefRe.ReplaceAllFunc(content, func(m []byte) []byte {
+		parts := anchorHrefRe.FindSubmatch(m)
+		if normalized, found := rewritten[string(parts[2])]; found {
+			return append(append(parts[1], normalized...), parts[3]...)
+		}
+		return m
+	})
+}
+
+// SummaryDivider denotes where content summarization should end. The default is "<!--more-->".
+var SummaryDivider = []byte("<!--more-->")
+
+// Stats holds content statistics for a rendered page.
+type Stats struct {
+	// WordCount is the number of words in the content, with HTML
+	// tags stripped, counted as in TotalWords.
+	WordCount int
+
+	// RuneCount is the number of runes in the content with HTML
+	// tags stripped.
+	RuneCount int
+
+	// ReadingTime is the estimated reading time in minutes.
+	ReadingTime int
+
+	// HasSummaryDivider is whether the content contains a summary divider.
+	HasSummaryDivider bool
+}
+
+// ContentStats computes word count, character count, reading time and
+// summary divider presence for content in a single pass, stripping HTML
+// tags the same cheap way as StripHTML.
+func ContentStats(content []byte) Stats {
+	var stats Stats
+
+	inTag := false
+	inWord := false
+	for _, r := range string(content) {
+		switch {
+		case r == '<':
+			inTag = true
+			inWord = false
+		case r == '>':
+			inTag = false
+		case inTag:
+		default:
+			stats.RuneCount++
+			wasInWord := inWord
+			inWord = !unicode.IsSpace(r)
+			if inWord && !wasInWord {
+				stats.WordCount++
+			}
+		}
+	}
+
+	// Same formula as used for Page.ReadingTime for non-CJK languages.
+	stats.ReadingTime = (stats.WordCount + 212) / 213
+	stats.HasSummaryDivider = bytes.Contains(content, SummaryDivider)
+
+	return stats
+}
+
+// ProseCodeStats scans markdown source and counts words in prose and lines
+// in code separately, so fenced code blocks (``` or ~~~, with or without a
+// language hint) and indented code blocks don't inflate the prose word
+// count. A closing fence must use the same character as and be at least as
+// long as the opening fence; shorter or mismatched fences are treated as
+// code content.
+func ProseCodeStats(content []byte) (proseWords, codeLines int) {
+	var (
+		inFence   bool
+		fenceChar byte
+		fenceLen  int
+	)
+
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		if inFence {
+			if indent <= 3 && fenceRun(trimmed, fenceChar) >= fenceLen && strings.TrimRight(trimmed, string(fenceChar)+" ") == "" {
+				inFence = false
+				continue
+			}
+			codeLines++
+			continue
+		}
+
+		if indent <= 3 && len(trimmed) > 0 && (trimmed[0] == '`' || trimmed[0] == '~') {
+			if n := fenceRun(trimmed, trimmed[0]); n >= 3 {
+				inFence = true
+				fenceChar = trimmed[0]
+				fenceLen = n
+				continue
+			}
+		}
+
+		if (indent >= 4 || strings.HasPrefix(line, "\t")) && strings.TrimSpace(line) != "" {
+			codeLines++
+			continue
+		}
+
+		proseWords += TotalWords(line)
+	}
+
+	return
+}
+
+// fenceRun returns the length of the run of c at the start of s.
+func fenceRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
 // TotalWords counts instance of one or more consecutive white space
 // characters, as defined by unicode.IsSpace, in s.
 // This is a cheaper way of word counting than the obvious len(strings.Fields(s)).
@@ -168,6 +1078,33 @@ func TotalWords(s string) int {
 	return n
 }
 
+// TotalWordsSkipPunctuation counts words in s like TotalWords, but skips
+// tokens composed entirely of punctuation and symbol runes, so e.g. the
+// em-dash in "one — two" and the bullets of a list don't count as words.
+// TotalWords keeps counting them for backward compatibility.
+func TotalWordsSkipPunctuation(s string) int {
+	n := 0
+	inWord := false
+	hasContent := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if inWord && hasContent {
+				n++
+			}
+			inWord, hasContent = false, false
+			continue
+		}
+		inWord = true
+		if !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			hasContent = true
+		}
+	}
+	if inWord && hasContent {
+		n++
+	}
+	return n
+}
+
 // TruncateWordsByRune truncates words by runes.
 func (c *ContentSpec) TruncateWordsByRune(in []string) (string, bool) {
 	words := make([]string, len(in))
@@ -234,9 +1171,164 @@ func (c *ContentSpec) TruncateWordsToWholeSentence(s string) (string, bool) {
 		return s, false
 	}
 
+	if c.Cfg.SummaryBalanceDelimiters() {
+		endIndex = balanceTruncatedDelimiters(s, endIndex)
+	}
+
 	return strings.TrimSpace(s[:endIndex]), endIndex < len(s)
 }
 
+// balanceDelimitersLookahead is how far past the truncation point
+// balanceTruncatedDelimiters will look for closing delimiters before it
+// gives up and backs off instead.
+const balanceDelimitersLookahead = 120
+
+// balanceTruncatedDelimiters adjusts the truncation point end so that paired
+// delimiters (parentheses, brackets and double quotes) in s[:end] stay
+// balanced. If the missing closing delimiters are found close after end, the
+// truncation point is extended to include them; otherwise it backs off to
+// just before the first unclosed opening delimiter.
+func balanceTruncatedDelimiters(s string, end int) int {
+	type open struct {
+		closer rune
+		pos    int
+	}
+	var stack []open
+
+	track := func(r rune, i int) bool {
+		switch r {
+		case '(':
+			stack = append(stack, open{')', i})
+		case '[':
+			stack = append(stack, open{']', i})
+		case '"':
+			if len(stack) > 0 && stack[len(stack)-1].closer == '"' {
+				stack = stack[:len(stack)-1]
+			} else {
+				stack = append(stack, open{'"', i})
+			}
+		case ')', ']':
+			if len(stack) > 0 && stack[len(stack)-1].closer == r {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		return len(stack) == 0
+	}
+
+	for i, r := range s[:end] {
+		track(r, i)
+	}
+
+	if len(stack) == 0 {
+		return end
+	}
+
+	lookahead := s[end:]
+	if len(lookahead) > balanceDelimitersLookahead {
+		lookahead = lookahead[:balanceDelimitersLookahead]
+	}
+	for i, r := range lookahead {
+		if track(r, end+i) {
+			newEnd := end + i + utf8.RuneLen(r)
+			// Include any sentence terminators that immediately follow the
+			// closing delimiter.
+			for _, rr := range s[newEnd:] {
+				if !isEndOfSentence(rr) {
+					break
+				}
+				newEnd += utf8.RuneLen(rr)
+			}
+			return newEnd
+		}
+	}
+
+	return stack[0].pos
+}
+
+// voidElements are the HTML elements that have no closing tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// TruncateWordsHTML truncates rendered HTML to the configured summary
+// length, counting only visible text words so the cut never lands inside a
+// tag. Elements still open at the cut are closed in reverse order, with
+// ellipsis, if non-empty, appended before the closing tags. It also returns
+// whether the content was truncated.
+func (c *ContentSpec) TruncateWordsHTML(content []byte, ellipsis string) ([]byte, bool) {
+	limit := c.Cfg.SummaryLength()
+	var (
+		buf   bytes.Buffer
+		open  []string
+		count int
+		skip  int
+	)
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return content, false
+		}
+		raw := z.Raw()
+		if tt == html.TextToken && skip == 0 {
+			// Find where the word after the limit starts, if it's in
+			// this text node.
+			text := string(raw)
+			cut := -1
+			inWord := false
+			for i, r := range text {
+				wasInWord := inWord
+				inWord = !unicode.IsSpace(r)
+				if inWord && !wasInWord {
+					if count >= limit {
+						cut = i
+						break
+					}
+					count++
+				}
+			}
+			if cut == -1 {
+				buf.Write(raw)
+				continue
+			}
+			buf.WriteString(strings.TrimRightFunc(text[:cut], unicode.IsSpace))
+			buf.WriteString(ellipsis)
+			for i := len(open) - 1; i >= 0; i-- {
+				buf.WriteString("</" + open[i] + ">")
+			}
+			return buf.Bytes(), true
+		}
+		switch tt {
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style":
+				skip++
+			}
+			if !voidElements[string(name)] {
+				open = append(open, string(name))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style":
+				if skip > 0 {
+					skip--
+				}
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == string(name) {
+					open = append(open[:i], open[i+1:]...)
+					break
+				}
+			}
+		}
+		buf.Write(raw)
+	}
+}
+
 // TrimShortHTML removes the outer tags from HTML input where (a) the opening
 // tag is present only once with the input, and (b) the opening and closing
 // tags wrap the input after white space removal.
@@ -15,6 +15,7 @@ package helpers_test
 
 import (
 	"bytes"
+	"errors"
 	"html/template"
 	"strings"
 	"testing"
@@ -212,6 +213,30 @@ func TestTotalWords(t *testing.T) {
 	}
 }
 
+func TestTotalWordsSkipPunctuation(t *testing.T) {
+	for i, this := range []struct {
+		s         string
+		words     int
+		skipWords int
+	}{
+		// Em-dashes and bullets count as words by default, but not when
+		// punctuation-only tokens are skipped.
+		{"one — two", 3, 2},
+		{"• first\n• second\n• third", 6, 3},
+		{"Two, Words!", 2, 2},
+		{"it's fine - really", 4, 3},
+		{"*** ---", 2, 0},
+		{"", 0, 0},
+	} {
+		if got := helpers.TotalWords(this.s); got != this.words {
+			t.Errorf("[%d] TotalWords(%q) = %d, want %d", i, this.s, got, this.words)
+		}
+		if got := helpers.TotalWordsSkipPunctuation(this.s); got != this.skipWords {
+			t.Errorf("[%d] TotalWordsSkipPunctuation(%q) = %d, want %d", i, this.s, got, this.skipWords)
+		}
+	}
+}
+
 func BenchmarkTotalWords(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -221,3 +246,436 @@ func BenchmarkTotalWords(b *testing.B) {
 		}
 	}
 }
+
+func TestCountElements(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		content  string
+		images   int
+		links    int
+		headings int
+	}{
+		{"", 0, 0, 0},
+		{"<p>Plain text</p>", 0, 0, 0},
+		{`<h1>Title</h1><p><img src="a.png"><a href="https://example.org">x</a></p>`, 1, 1, 1},
+		{`<a href="#fragment">anchor</a><a href="/page/">page</a>`, 0, 1, 0},
+		{`<h2>A</h2><h3>B</h3><img src="a.png"/><img src="b.png"/>`, 2, 0, 2},
+		// Malformed HTML.
+		{`<h1>Unclosed<img src="a.png"<a href="x">`, 1, 0, 1},
+	} {
+		images, links, headings := helpers.CountElements([]byte(test.content))
+		c.Assert(images, qt.Equals, test.images, qt.Commentf("images: %s", test.content))
+		c.Assert(links, qt.Equals, test.links, qt.Commentf("links: %s", test.content))
+		c.Assert(headings, qt.Equals, test.headings, qt.Commentf("headings: %s", test.content))
+	}
+}
+
+func TestNormalizeHeadingAnchors(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(helpers.NormalizeHeadingAnchor("My Heading!"), qt.Equals, "my-heading")
+	c.Assert(helpers.NormalizeHeadingAnchor("Already-fine_1"), qt.Equals, "already-fine_1")
+
+	in := `<nav><ul><li><a href="#My_Heading.1">My Heading</a></li></ul></nav>` +
+		`<h2 id="My_Heading.1">My Heading</h2><h3 id="ok">Ok</h3><a href="#ok">x</a><a href="#external">y</a>`
+	out := string(helpers.NormalizeHeadingAnchors([]byte(in)))
+
+	c.Assert(out, qt.Contains, `<h2 id="my_heading1">`)
+	c.Assert(out, qt.Contains, `<a href="#my_heading1">`)
+	c.Assert(out, qt.Contains, `<h3 id="ok">`)
+	c.Assert(out, qt.Contains, `<a href="#ok">`)
+	c.Assert(out, qt.Contains, `<a href="#external">`)
+}
+
+func TestFirstParagraph(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		content string
+		expect  string
+	}{
+		{"", ""},
+		{"<p>First</p><p>Second</p>", "<p>First</p>"},
+		// Skip paragraphs with only an image.
+		{`<p><img src="a.png"></p><p>Text</p>`, "<p>Text</p>"},
+		// Skip empty paragraphs.
+		{"<p>  </p><p>Text</p>", "<p>Text</p>"},
+		{"<div><p>Nested</p></div>", "<p>Nested</p>"},
+		// Keep inline markup.
+		{"<p>Some <em>emphasis</em></p>", "<p>Some <em>emphasis</em></p>"},
+		{`<p><img src="a.png"></p>`, ""},
+	} {
+		got := string(helpers.FirstParagraph([]byte(test.content)))
+		c.Assert(got, qt.Equals, test.expect, qt.Commentf("content: %s", test.content))
+	}
+}
+
+func TestContentStats(t *testing.T) {
+	c := qt.New(t)
+
+	stats := helpers.ContentStats([]byte("<p>Hello <b>big</b> world</p>\n<!--more-->\n<p>Moar</p>"))
+	c.Assert(stats.WordCount, qt.Equals, 4)
+	c.Assert(stats.HasSummaryDivider, qt.Equals, true)
+	c.Assert(stats.ReadingTime, qt.Equals, 1)
+
+	stats = helpers.ContentStats([]byte("one two"))
+	c.Assert(stats.WordCount, qt.Equals, 2)
+	c.Assert(stats.RuneCount, qt.Equals, 7)
+	c.Assert(stats.HasSummaryDivider, qt.Equals, false)
+
+	stats = helpers.ContentStats(nil)
+	c.Assert(stats.WordCount, qt.Equals, 0)
+	c.Assert(stats.ReadingTime, qt.Equals, 0)
+}
+
+func TestProseCodeStats(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		name       string
+		content    string
+		proseWords int
+		codeLines  int
+	}{
+		{"prose only", "one two three", 3, 0},
+		{"backtick fence", "one two\n```go\na := 1\nb := 2\n```\nthree", 3, 2},
+		{"tilde fence", "~~~\ncode here\n~~~\nprose", 1, 1},
+		{"nested shorter fence is content", "~~~~\n```\ninner\n```\n~~~~\nend", 1, 3},
+		{"mismatched closing fence too short", "````\ncode\n```\nstill code\n````\n", 0, 3},
+		{"longer closing fence closes", "```\ncode\n`````\nprose", 1, 1},
+		{"indented code block", "prose line\n\n    indented code\n\nmore prose", 4, 1},
+		{"unclosed fence", "```go\na := 1", 0, 1},
+	} {
+		proseWords, codeLines := helpers.ProseCodeStats([]byte(test.content))
+		c.Assert(proseWords, qt.Equals, test.proseWords, qt.Commentf(test.name))
+		c.Assert(codeLines, qt.Equals, test.codeLines, qt.Commentf(test.name))
+	}
+}
+
+func TestRewriteImages(t *testing.T) {
+	c := qt.New(t)
+
+	resolve := func(src string) (string, error) {
+		if src == "fail.jpg" {
+			return "", errors.New("no such image")
+		}
+		return "/resized/" + src, nil
+	}
+
+	in := `<p><img alt="a" src="photo.jpg"><img src="https://example.org/b.png">` +
+		`<img src="//cdn.example.org/c.png"><img src="data:image/png;base64,xx">` +
+		`<img src="fail.jpg"></p>`
+	out := string(helpers.RewriteImages([]byte(in), resolve))
+
+	c.Assert(out, qt.Contains, `src="/resized/photo.jpg"`)
+	c.Assert(out, qt.Contains, `src="https://example.org/b.png"`)
+	c.Assert(out, qt.Contains, `src="//cdn.example.org/c.png"`)
+	c.Assert(out, qt.Contains, `src="data:image/png;base64,xx"`)
+	c.Assert(out, qt.Contains, `src="fail.jpg"`)
+}
+
+func TestExtractHeadings(t *testing.T) {
+	c := qt.New(t)
+
+	content := `<h1 id="title">The  Title</h1><p>Text</p>` +
+		`<h2>No ID</h2>` +
+		`<h3 id="deep"><em>Nested</em> text</h3>`
+
+	headings := helpers.ExtractHeadings([]byte(content))
+	c.Assert(headings, qt.DeepEquals, []helpers.Heading{
+		{Level: 1, ID: "title", Text: "The Title"},
+		{Level: 2, ID: "", Text: "No ID"},
+		{Level: 3, ID: "deep", Text: "Nested text"},
+	})
+
+	c.Assert(helpers.ExtractHeadings(nil), qt.IsNil)
+}
+
+func TestTruncateWordsToWholeSentenceBalanced(t *testing.T) {
+	c := qt.New(t)
+
+	type test struct {
+		input, expected string
+		max             int
+		truncated       bool
+	}
+	data := []test{
+		// The closing delimiter is nearby, so the cut extends to include it.
+		{"To be (or not. To be). That is it.", "To be (or not. To be).", 3, true},
+		{`He said "To be. Or not." And left. For good.`, `He said "To be. Or not."`, 3, true},
+		// No closing delimiter: back off to before the opener.
+		{"To be (or not. There is no closing here at all end", "To be", 3, true},
+		// Balanced input is unchanged.
+		{"To be. Or not to be. That's the question.", "To be.", 1, true},
+	}
+	for i, d := range data {
+		cfg := config.New()
+		cfg.Set("summaryLength", d.max)
+		cfg.Set("summaryBalanceDelimiters", true)
+		spec := newTestContentSpec(cfg)
+		output, truncated := spec.TruncateWordsToWholeSentence(d.input)
+		c.Assert(output, qt.Equals, d.expected, qt.Commentf("case %d", i))
+		c.Assert(truncated, qt.Equals, d.truncated, qt.Commentf("case %d", i))
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	c := qt.New(t)
+	spec := newTestContentSpec(nil)
+
+	s, err := spec.RenderPlain("markdown", []byte("# Hi\n\nSome *emphasis* &amp; [a link](/foo/).\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "Hi Some emphasis & a link.")
+
+	// HTML input skips the render step.
+	s, err = spec.RenderPlain("html", []byte("<p>Plain <b>bold</b></p><script>var x;</script>"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, "Plain bold")
+
+	_, err = spec.RenderPlain("nosuchformat", []byte("x"))
+	c.Assert(err, qt.IsNotNil)
+}
+
+func TestSplitByHeading(t *testing.T) {
+	c := qt.New(t)
+
+	content := []byte(`<p>Intro.</p><h2 id="one">One</h2><p>First.</p><h3 id="sub">Sub</h3><h2 id="two">Two</h2><p>Second.</p>`)
+
+	chunks := helpers.SplitByHeading(content, 2)
+	c.Assert(chunks, qt.HasLen, 3)
+	c.Assert(string(chunks[0]), qt.Equals, `<p>Intro.</p>`)
+	c.Assert(string(chunks[1]), qt.Equals, `<h2 id="one">One</h2><p>First.</p><h3 id="sub">Sub</h3>`)
+	c.Assert(string(chunks[2]), qt.Equals, `<h2 id="two">Two</h2><p>Second.</p>`)
+
+	// Content starting with a matching heading has no leading chunk.
+	chunks = helpers.SplitByHeading([]byte(`<h2>A</h2><p>a</p><h2>B</h2>`), 2)
+	c.Assert(chunks, qt.HasLen, 2)
+	c.Assert(string(chunks[0]), qt.Equals, `<h2>A</h2><p>a</p>`)
+
+	// No matching headings.
+	chunks = helpers.SplitByHeading(content, 4)
+	c.Assert(chunks, qt.HasLen, 1)
+	c.Assert(string(chunks[0]), qt.Equals, string(content))
+}
+
+func TestRenderBytes(t *testing.T) {
+	c := qt.New(t)
+
+	// Unlimited by default.
+	spec := newTestContentSpec(nil)
+	b, err := spec.RenderBytes("markdown", []byte("Some *emphasis*.\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "<p>Some <em>emphasis</em>.</p>\n")
+
+	cfg := config.New()
+	cfg.Set("maxRenderedContentSize", 20)
+	spec = newTestContentSpec(cfg)
+
+	b, err = spec.RenderBytes("markdown", []byte("Short.\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "<p>Short.</p>\n")
+
+	b, err = spec.RenderBytes("markdown", []byte("This is too long to fit.\n"))
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(errors.Is(err, helpers.ErrRenderedContentTooLarge), qt.IsTrue)
+	c.Assert(b, qt.HasLen, 20)
+
+	_, err = spec.RenderBytes("nosuchformat", []byte("x"))
+	c.Assert(err, qt.IsNotNil)
+}
+
+func TestRenderBytesMinify(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	cfg.Set("minifyRenderedContent", true)
+	spec := newTestContentSpec(cfg)
+
+	b, err := spec.RenderBytes("markdown", []byte("# Heading\n\nSome *emphasis*.\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "<h1 id=heading>Heading</h1><p>Some <em>emphasis</em>.</p>")
+
+	// Off by default.
+	spec = newTestContentSpec(nil)
+	b, err = spec.RenderBytes("markdown", []byte("Some *emphasis*.\n"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "<p>Some <em>emphasis</em>.</p>\n")
+}
+
+func TestValidateAnchors(t *testing.T) {
+	c := qt.New(t)
+
+	content := `<h1 id="intro">Intro</h1>` +
+		`<p><a href="#intro">ok</a> <a href="#missing">broken</a></p>` +
+		`<p><a href="https://example.org/#external">external</a> <a href="/page/">page</a></p>` +
+		`<p><a href="#also-missing">broken too</a> <span id="late">late ID</span> <a href="#late">ok</a></p>`
+
+	c.Assert(helpers.ValidateAnchors([]byte(content)), qt.DeepEquals, []string{"missing", "also-missing"})
+
+	c.Assert(helpers.ValidateAnchors([]byte(`<p>No links</p>`)), qt.IsNil)
+
+	// Malformed HTML.
+	c.Assert(helpers.ValidateAnchors([]byte(`<a href="#x">unclosed`)), qt.DeepEquals, []string{"x"})
+}
+
+func TestDuplicateHeadingIDs(t *testing.T) {
+	c := qt.New(t)
+
+	content := `<h2 id="setup">Setup</h2>` +
+		`<h2 id="usage">Usage</h2>` +
+		`<h3 id="setup">Setup</h3>` +
+		`<h2 id="faq">FAQ</h2>` +
+		`<h4 id="faq">FAQ</h4>` +
+		`<h5 id="setup">Setup</h5>` +
+		// Ids on other elements don't count.
+		`<div id="usage"></div>`
+
+	c.Assert(helpers.DuplicateHeadingIDs([]byte(content)), qt.DeepEquals, []string{"setup", "faq"})
+
+	c.Assert(helpers.DuplicateHeadingIDs([]byte(`<h1 id="a">A</h1><h2 id="b">B</h2>`)), qt.IsNil)
+
+	// Headings without ids and malformed HTML are tolerated.
+	c.Assert(helpers.DuplicateHeadingIDs([]byte(`<h1>A</h1><h2 id="x">X<h2 id="x">X`)), qt.DeepEquals, []string{"x"})
+}
+
+func TestStripTrackingParams(t *testing.T) {
+	c := qt.New(t)
+
+	in := `<p><a href="https://example.org/page/?utm_source=x&utm_medium=y&id=7">a</a>` +
+		` <a href="/local/?fbclid=abc">b</a>` +
+		` <a href="https://example.org/clean/?page=2">c</a>` +
+		` <a href="#fragment">d</a></p>`
+
+	got := string(helpers.StripTrackingParams([]byte(in), nil))
+
+	c.Assert(strings.Contains(got, "utm_source"), qt.IsFalse)
+	c.Assert(strings.Contains(got, "fbclid"), qt.IsFalse)
+	c.Assert(strings.Contains(got, `href="https://example.org/page/?id=7"`), qt.IsTrue)
+	c.Assert(strings.Contains(got, `href="/local/"`), qt.IsTrue)
+	c.Assert(strings.Contains(got, `href="https://example.org/clean/?page=2"`), qt.IsTrue)
+	c.Assert(strings.Contains(got, `href="#fragment"`), qt.IsTrue)
+
+	// Explicit list overrides the defaults.
+	got = string(helpers.StripTrackingParams([]byte(`<a href="/p/?ref=x&utm_source=y">a</a>`), []string{"ref"}))
+	c.Assert(strings.Contains(got, "ref=x"), qt.IsFalse)
+	c.Assert(strings.Contains(got, "utm_source=y"), qt.IsTrue)
+}
+
+func TestDetectFormat(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		filename string
+		content  string
+		expect   string
+	}{
+		{"page.md", "", "markdown"},
+		{"page.adoc", "", "asciidoc"},
+		{"page.rst", "", "rst"},
+		{"page.pdc", "", "pandoc"},
+		{"page.org", "", "org"},
+		{"page.html", "", "html"},
+		// No known extension; sniff the content.
+		{"page.txt", "= My Title\n\nSome text.", "asciidoc"},
+		{"page.txt", "% My Title\n% Author\n", "pandoc"},
+		{"page.txt", "Intro.\n\n.. note::\n   Careful.\n", "rst"},
+		{"page.txt", "# My Title\n", "markdown"},
+		{"page", "", "markdown"},
+	} {
+		c.Assert(helpers.DetectFormat(test.filename, []byte(test.content)), qt.Equals, test.expect, qt.Commentf("%s: %q", test.filename, test.content))
+	}
+}
+
+func TestAddHeadingIDs(t *testing.T) {
+	c := qt.New(t)
+
+	spec := newTestContentSpec(nil)
+
+	content := `<h1>My Title</h1>
+<h2 id="keep-me">Already Set</h2>
+<h2>Repeated</h2>
+<h2>Repeated</h2>
+<p>Not a heading.</p>`
+
+	b, err := spec.AddHeadingIDs([]byte(content), "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `<h1 id="my-title">My Title</h1>
+<h2 id="keep-me">Already Set</h2>
+<h2 id="repeated">Repeated</h2>
+<h2 id="repeated-1">Repeated</h2>
+<p>Not a heading.</p>`)
+
+	b, err = spec.AddHeadingIDs([]byte("<h3>Deep <em>Link</em></h3>"), "doc-")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `<h3 id="doc-deep-link">Deep <em>Link</em></h3>`)
+}
+
+func TestExtractFigures(t *testing.T) {
+	c := qt.New(t)
+
+	content := `<p>Intro.</p>
+<figure>
+<img src="/a.png" alt="Alt A">
+<figcaption>Caption <em>A</em></figcaption>
+</figure>
+<figure><img src="/b.png" alt="Alt B"></figure>
+<p><img src="/c.png" alt="Alt C"></p>
+<figure><figcaption>No image here.</figcaption></figure>`
+
+	c.Assert(helpers.ExtractFigures([]byte(content)), qt.DeepEquals, []helpers.Figure{
+		{Src: "/a.png", Alt: "Alt A", Caption: "Caption A"},
+		{Src: "/b.png", Alt: "Alt B", Caption: "Alt B"},
+		{Src: "/c.png", Alt: "Alt C"},
+	})
+
+	c.Assert(helpers.ExtractFigures([]byte("<p>No images.</p>")), qt.IsNil)
+}
+
+func TestTruncateWordsHTML(t *testing.T) {
+	c := qt.New(t)
+
+	type test struct {
+		input     string
+		expected  string
+		max       int
+		truncated bool
+	}
+	data := []test{
+		{"<p>one two three four</p>", "<p>one two three four</p>", 5, false},
+		{"<p>one two three four five six</p>", "<p>one two three four five …</p>", 5, true},
+		// Nested inline tags crossing the cut point stay balanced.
+		{"<p>one <em>two <strong>three four</strong> five</em> six</p>", "<p>one <em>two <strong>three …</strong></em></p>", 3, true},
+		// The cut can land in a later element.
+		{"<p>one two</p><p>three four five</p>", "<p>one two</p><p>three …</p>", 3, true},
+		// Void elements don't need closing.
+		{"<p>one<br>two three</p>", "<p>one<br>two …</p>", 2, true},
+		// Script content is not visible text.
+		{"<script>var a = 1;</script><p>one two three</p>", "<script>var a = 1;</script><p>one two …</p>", 2, true},
+	}
+	for i, d := range data {
+		cfg := config.New()
+		cfg.Set("summaryLength", d.max)
+		spec := newTestContentSpec(cfg)
+		output, truncated := spec.TruncateWordsHTML([]byte(d.input), " …")
+		c.Assert(string(output), qt.Equals, d.expected, qt.Commentf("case %d", i))
+		c.Assert(truncated, qt.Equals, d.truncated, qt.Commentf("case %d", i))
+	}
+}
+
+func TestMetaDescription(t *testing.T) {
+	c := qt.New(t)
+
+	// Content before the summary divider is preferred.
+	content := "<p>The short intro.</p>\n<!--more-->\n<p>Much longer body text that should not appear.</p>"
+	c.Assert(helpers.MetaDescription([]byte(content), 160), qt.Equals, "The short intro.")
+
+	// Without a divider the content is cut on a word boundary.
+	content = "<p>One two three &amp; four five six.</p>"
+	c.Assert(helpers.MetaDescription([]byte(content), 160), qt.Equals, "One two three & four five six.")
+	c.Assert(helpers.MetaDescription([]byte(content), 17), qt.Equals, "One two three & …")
+	c.Assert(helpers.MetaDescription([]byte(content), 12), qt.Equals, "One two …")
+
+	// maxChars <= 0 means no limit.
+	c.Assert(helpers.MetaDescription([]byte(content), 0), qt.Equals, "One two three & four five six.")
+}
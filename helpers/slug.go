@@ -0,0 +1,89 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gohugoio/hugo/common/text"
+)
+
+// SlugOptions configures MakeSlug.
+type SlugOptions struct {
+	// Lowercase the result.
+	Lowercase bool
+
+	// Transliterate accented characters to their ASCII base form, so
+	// e.g. "é" becomes "e" instead of being kept as is.
+	Transliterate bool
+
+	// Separator replaces runs of non-alphanumeric characters. An empty
+	// Separator means "-".
+	Separator string
+
+	// MaxLength is the maximum length of the slug in runes; 0 means no
+	// limit. Truncation happens on a word boundary, so a slug is never
+	// cut mid-word unless it consists of a single long word.
+	MaxLength int
+}
+
+// MakeSlug returns a slug of text suitable for anchors, taxonomy terms and
+// filenames: any run of characters that aren't letters or digits collapses
+// to a single separator, and leading and trailing separators are dropped.
+func MakeSlug(s string, opts SlugOptions) string {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+
+	if opts.Transliterate {
+		s = text.RemoveAccentsString(s)
+	}
+	if opts.Lowercase {
+		s = strings.ToLower(s)
+	}
+
+	var (
+		sb         strings.Builder
+		pendingSep bool
+	)
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingSep && sb.Len() > 0 {
+				sb.WriteString(sep)
+			}
+			pendingSep = false
+			sb.WriteRune(r)
+		} else {
+			pendingSep = true
+		}
+	}
+	s = sb.String()
+
+	if opts.MaxLength > 0 {
+		runes := []rune(s)
+		if len(runes) > opts.MaxLength {
+			cut := string(runes[:opts.MaxLength])
+			if !strings.HasPrefix(string(runes[opts.MaxLength:]), sep) {
+				if i := strings.LastIndex(cut, sep); i > 0 {
+					cut = cut[:i]
+				}
+			}
+			s = strings.TrimSuffix(cut, sep)
+		}
+	}
+
+	return s
+}
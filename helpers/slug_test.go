@@ -0,0 +1,52 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/helpers"
+)
+
+func TestMakeSlug(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		in     string
+		opts   helpers.SlugOptions
+		expect string
+	}{
+		{"Hello World", helpers.SlugOptions{}, "Hello-World"},
+		{"Hello World", helpers.SlugOptions{Lowercase: true}, "hello-world"},
+		// Unicode transliteration.
+		{"Crème Brûlée", helpers.SlugOptions{Lowercase: true, Transliterate: true}, "creme-brulee"},
+		{"Crème Brûlée", helpers.SlugOptions{Lowercase: true}, "crème-brûlée"},
+		// Runs of non-alphanumerics collapse to one separator.
+		{"a -- b!! c", helpers.SlugOptions{}, "a-b-c"},
+		{"  leading & trailing  ", helpers.SlugOptions{}, "leading-trailing"},
+		// Custom separator.
+		{"a b c", helpers.SlugOptions{Separator: "_"}, "a_b_c"},
+		// Truncation happens on a word boundary.
+		{"one two three", helpers.SlugOptions{MaxLength: 9}, "one-two"},
+		{"one two three", helpers.SlugOptions{MaxLength: 7}, "one-two"},
+		{"one two three", helpers.SlugOptions{MaxLength: 6}, "one"},
+		// A single long word is cut mid-word.
+		{"extraordinary", helpers.SlugOptions{MaxLength: 5}, "extra"},
+		{"short", helpers.SlugOptions{MaxLength: 10}, "short"},
+		{"", helpers.SlugOptions{}, ""},
+	} {
+		c.Assert(helpers.MakeSlug(test.in, test.opts), qt.Equals, test.expect, qt.Commentf("in: %q opts: %+v", test.in, test.opts))
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Summarizer produces a summary of content. The built-in strategies are
+// created with NewSummarizer; embedders can plug in their own by
+// implementing this interface directly.
+type Summarizer interface {
+	// Summarize returns the summary of content and whether anything was
+	// cut off in the process.
+	Summarize(content []byte) (summary []byte, truncated bool)
+}
+
+// NewSummarizer returns the built-in summary strategy with the given name:
+//
+//   - "sentences" (the default): whole sentences up to summaryLength words.
+//   - "words": exactly the first summaryLength words.
+//   - "firstparagraph": everything up to the end of the first paragraph.
+//
+// Unknown names are an error.
+func (c *ContentSpec) NewSummarizer(name string) (Summarizer, error) {
+	switch strings.ToLower(name) {
+	case "", "sentences":
+		return sentencesSummarizer{c: c}, nil
+	case "words":
+		return wordsSummarizer{c: c}, nil
+	case "firstparagraph":
+		return firstParagraphSummarizer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown summary strategy %q", name)
+	}
+}
+
+// Summarizer returns the summary strategy selected with the summaryStrategy
+// site config.
+func (c *ContentSpec) Summarizer() (Summarizer, error) {
+	return c.NewSummarizer(c.Cfg.SummaryStrategy())
+}
+
+// sentencesSummarizer truncates on sentence boundaries, see
+// TruncateWordsToWholeSentence.
+type sentencesSummarizer struct {
+	c *ContentSpec
+}
+
+func (s sentencesSummarizer) Summarize(content []byte) ([]byte, bool) {
+	summary, truncated := s.c.TruncateWordsToWholeSentence(string(content))
+	return []byte(summary), truncated
+}
+
+// wordsSummarizer truncates after summaryLength words, see
+// TruncateWordsByRune.
+type wordsSummarizer struct {
+	c *ContentSpec
+}
+
+func (s wordsSummarizer) Summarize(content []byte) ([]byte, bool) {
+	summary, truncated := s.c.TruncateWordsByRune(strings.Fields(string(content)))
+	return []byte(summary), truncated
+}
+
+// firstParagraphSummarizer cuts at the first blank line, i.e. the end of
+// the first paragraph.
+type firstParagraphSummarizer struct{}
+
+func (firstParagraphSummarizer) Summarize(content []byte) ([]byte, bool) {
+	if i := bytes.Index(content, []byte("\n\n")); i != -1 {
+		return content[:i], true
+	}
+	return bytes.TrimRight(content, "\n"), false
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestSummarizer(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	cfg.Set("summaryLength", 5)
+	spec := newTestContentSpec(cfg)
+
+	content := []byte("One two three four. Five six seven eight nine ten.\n\nSecond paragraph.")
+
+	for _, test := range []struct {
+		strategy  string
+		expected  string
+		truncated bool
+	}{
+		{"sentences", "One two three four. Five six seven eight nine ten.", true},
+		{"words", "One two three four. Five", true},
+		{"firstparagraph", "One two three four. Five six seven eight nine ten.", true},
+	} {
+		s, err := spec.NewSummarizer(test.strategy)
+		c.Assert(err, qt.IsNil)
+		summary, truncated := s.Summarize(content)
+		c.Assert(string(summary), qt.Equals, test.expected, qt.Commentf(test.strategy))
+		c.Assert(truncated, qt.Equals, test.truncated, qt.Commentf(test.strategy))
+	}
+
+	// The empty name is the default strategy.
+	s, err := spec.NewSummarizer("")
+	c.Assert(err, qt.IsNil)
+	summary, truncated := s.Summarize([]byte("Short."))
+	c.Assert(string(summary), qt.Equals, "Short.")
+	c.Assert(truncated, qt.IsFalse)
+
+	_, err = spec.NewSummarizer("nosuchstrategy")
+	c.Assert(err, qt.IsNotNil)
+
+	// Selected via config.
+	cfg = config.New()
+	cfg.Set("summaryLength", 5)
+	cfg.Set("summaryStrategy", "words")
+	spec = newTestContentSpec(cfg)
+	s, err = spec.Summarizer()
+	c.Assert(err, qt.IsNil)
+	summary, _ = s.Summarize(content)
+	c.Assert(string(summary), qt.Equals, "One two three four. Five")
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"bytes"
+)
+
+// applyTableWrapper wraps each rendered <table> in a scroll container div,
+// e.g. <div class="table-responsive"><table>...</table></div>, so wide
+// tables can overflow horizontally instead of breaking the layout. Enabled
+// with wrapTables; the class is configured with tableWrapperClass. Tables
+// already wrapped in such a div are left alone. The rewrite operates on the
+// rendered HTML and so works for any source format.
+func (c *ContentSpec) applyTableWrapper(content []byte) []byte {
+	if !c.Cfg.WrapTables() || !bytes.Contains(content, []byte(tableOpen)) {
+		return content
+	}
+
+	class := c.Cfg.TableWrapperClass()
+	if class == "" {
+		class = "table-responsive"
+	}
+
+	return wrapTables(content, class)
+}
+
+const (
+	tableOpen  = "<table"
+	tableClose = "</table>"
+)
+
+func wrapTables(b []byte, class string) []byte {
+	var buf bytes.Buffer
+	wrapper := []byte(`<div class="` + class + `">`)
+
+	for {
+		i := indexTableOpen(b)
+		if i == -1 {
+			break
+		}
+		rest := b[i+len(tableOpen):]
+		end, ok := findTableEnd(rest)
+		if !ok {
+			// Unbalanced markup; leave the remainder alone.
+			break
+		}
+		table := b[i : i+len(tableOpen)+end+len(tableClose)]
+
+		if bytes.HasSuffix(bytes.TrimRight(b[:i], " \t\r\n"), wrapper) {
+			// Already wrapped.
+			buf.Write(b[:i])
+			buf.Write(table)
+		} else {
+			buf.Write(b[:i])
+			buf.Write(wrapper)
+			buf.Write(table)
+			buf.WriteString("</div>")
+		}
+		b = b[i+len(tableOpen)+end+len(tableClose):]
+	}
+
+	buf.Write(b)
+	return buf.Bytes()
+}
+
+// indexTableOpen returns the offset of the next <table> opening tag in b, or
+// -1. The tag may carry attributes.
+func indexTableOpen(b []byte) int {
+	off := 0
+	for {
+		i := bytes.Index(b[off:], []byte(tableOpen))
+		if i == -1 {
+			return -1
+		}
+		j := off + i + len(tableOpen)
+		if j < len(b) && (b[j] == '>' || b[j] == ' ' || b[j] == '\t' || b[j] == '\n') {
+			return off + i
+		}
+		off = j
+	}
+}
+
+// findTableEnd returns the offset of the </table> that closes the table b
+// starts inside of, taking nested tables into account.
+func findTableEnd(b []byte) (int, bool) {
+	var off, depth int
+	for {
+		no := indexTableOpen(b[off:])
+		nc := bytes.Index(b[off:], []byte(tableClose))
+		if nc == -1 {
+			return 0, false
+		}
+		if no != -1 && no < nc {
+			depth++
+			off += no + len(tableOpen)
+			continue
+		}
+		if depth == 0 {
+			return off + nc, true
+		}
+		depth--
+		off += nc + len(tableClose)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestRenderBytesWrapTables(t *testing.T) {
+	c := qt.New(t)
+
+	const table = "| A | B |\n|---|---|\n| 1 | 2 |\n"
+
+	cfg := config.New()
+	cfg.Set("wrapTables", true)
+	spec := newTestContentSpec(cfg)
+
+	// A single table.
+	b, err := spec.RenderBytes("markdown", []byte(table))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `<div class="table-responsive"><table>
+<thead>
+<tr>
+<th>A</th>
+<th>B</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>1</td>
+<td>2</td>
+</tr>
+</tbody>
+</table></div>
+`)
+
+	// Multiple tables each get their own wrapper.
+	b, err = spec.RenderBytes("markdown", []byte(table+"\nText between.\n\n"+table))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, "Text between.")
+	c.Assert(strings.Count(string(b), `<div class="table-responsive"><table>`), qt.Equals, 2)
+	c.Assert(strings.Count(string(b), "</table></div>"), qt.Equals, 2)
+
+	// Already wrapped tables are left alone.
+	wrapped := `<div class="table-responsive"><table><tr><td>1</td></tr></table></div>`
+	b, err = spec.RenderBytes("html", []byte(wrapped))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, wrapped)
+
+	// The wrapper class is configurable.
+	cfg = config.New()
+	cfg.Set("wrapTables", true)
+	cfg.Set("tableWrapperClass", "scroll-x")
+	spec = newTestContentSpec(cfg)
+	b, err = spec.RenderBytes("html", []byte("<table><tr><td>1</td></tr></table>"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `<div class="scroll-x"><table><tr><td>1</td></tr></table></div>`)
+
+	// Off by default.
+	spec = newTestContentSpec(config.New())
+	b, err = spec.RenderBytes("markdown", []byte(table))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Not(qt.Contains), "table-responsive")
+}
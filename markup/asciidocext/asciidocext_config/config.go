@@ -58,6 +58,14 @@ var (
 		"manpage":   true,
 	}
 
+	// HTMLBackend holds the backends that produce HTML output. Output from
+	// other backends is returned as is, without any HTML post processing.
+	HTMLBackend = map[string]bool{
+		"html5":  true,
+		"html5s": true,
+		"xhtml5": true,
+	}
+
 	DisallowedAttributes = map[string]bool{
 		"outdir": true,
 	}
@@ -65,6 +73,10 @@ var (
 
 // Config configures asciidoc.
 type Config struct {
+	// Path is an optional binary name to use instead of the default
+	// asciidoctor. It must resolve via $PATH and is subject to the
+	// security.exec.allow policy.
+	Path                 string
 	Backend              string
 	Extensions           []string
 	Attributes           map[string]string
@@ -297,6 +297,42 @@ my-attribute-name = "my value"
 	c.Assert(args[4], qt.Equals, "--no-header-footer")
 }
 
+func TestAsciidoctorDocumentAttributes(t *testing.T) {
+	c := qt.New(t)
+	cfg := config.FromTOMLConfigString(`
+[markup]
+[markup.asciidocext]
+`)
+	conf := testconfig.GetTestConfig(nil, cfg)
+	p, err := asciidocext.Provider.New(
+		converter.ProviderConfig{
+			Conf:   conf,
+			Logger: loggers.NewDefault(),
+		},
+	)
+	c.Assert(err, qt.IsNil)
+
+	ctx := converter.DocumentContext{
+		Attributes: map[string]string{
+			"icons":    "font",
+			"toclevel": "2",
+			// Disallowed and invalid attributes are dropped.
+			"outdir":    "/somewhere",
+			"--trace":   "x",
+			"multiline": "a\nb",
+		},
+	}
+
+	conv, err := p.New(ctx)
+	c.Assert(err, qt.IsNil)
+
+	ac := conv.(*internal.AsciidocConverter)
+	c.Assert(ac, qt.Not(qt.IsNil))
+
+	args := ac.ParseArgs(ctx)
+	c.Assert(args, qt.DeepEquals, []string{"-a", "icons=font", "-a", "toclevel=2", "--no-header-footer"})
+}
+
 func getProvider(c *qt.C, mConfStr string) converter.Provider {
 	confStr := `
 [security]
@@ -424,3 +460,34 @@ preserveTOC = true
 	c.Assert(toc.TableOfContents().Identifiers, qt.DeepEquals, collections.SortedStringSlice{"some-title"})
 	c.Assert(string(r.Bytes()), qt.Contains, "<div id=\"toc\" class=\"toc\">")
 }
+
+func TestAsciidoctorBackendHTMLDetection(t *testing.T) {
+	c := qt.New(t)
+
+	for backend, isHTML := range map[string]bool{
+		"html5":     true,
+		"html5s":    true,
+		"xhtml5":    true,
+		"docbook5":  false,
+		"docbook45": false,
+		"manpage":   false,
+	} {
+		mconf := markup_config.Default
+		mconf.AsciidocExt.Backend = backend
+		conf := testconfig.GetTestConfigSectionFromStruct("markup", mconf)
+
+		p, err := asciidocext.Provider.New(
+			converter.ProviderConfig{
+				Conf:   conf,
+				Logger: loggers.NewDefault(),
+			},
+		)
+		c.Assert(err, qt.IsNil)
+
+		conv, err := p.New(converter.DocumentContext{})
+		c.Assert(err, qt.IsNil)
+
+		ac := conv.(*internal.AsciidocConverter)
+		c.Assert(ac.HasHTMLBackend(), qt.Equals, isHTML, qt.Commentf("backend: %s", backend))
+	}
+}
@@ -3,6 +3,8 @@ package internal
 import (
 	"bytes"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gohugoio/hugo/common/hexec"
@@ -38,6 +40,11 @@ func (a *AsciidocConverter) Convert(ctx converter.RenderContext) (converter.Resu
 	if err != nil {
 		return nil, err
 	}
+	if !a.HasHTMLBackend() {
+		// The output is not HTML (e.g. DocBook or manpage), so skip the
+		// HTML specific TOC handling and return the output as is.
+		return converter.Bytes(b), nil
+	}
 	content, toc, err := a.extractTOC(b)
 	if err != nil {
 		return nil, err
@@ -48,6 +55,12 @@ func (a *AsciidocConverter) Convert(ctx converter.RenderContext) (converter.Resu
 	}, nil
 }
 
+// HasHTMLBackend reports whether the configured Asciidoctor backend
+// produces HTML output.
+func (a *AsciidocConverter) HasHTMLBackend() bool {
+	return asciidocext_config.HTMLBackend[a.Cfg.MarkupConfig().AsciidocExt.Backend]
+}
+
 func (a *AsciidocConverter) Supports(_ identity.Identity) bool {
 	return false
 }
@@ -55,7 +68,8 @@ func (a *AsciidocConverter) Supports(_ identity.Identity) bool {
 // GetAsciidocContent calls asciidoctor as an external helper
 // to convert AsciiDoc content to HTML.
 func (a *AsciidocConverter) GetAsciidocContent(src []byte, ctx converter.DocumentContext) ([]byte, error) {
-	if !HasAsciiDoc() {
+	binaryName := a.binaryName()
+	if binaryName == "" {
 		a.Cfg.Logger.Errorln("asciidoctor not found in $PATH: Please install.\n",
 			"                 Leaving AsciiDoc content unrendered.")
 		return src, nil
@@ -64,9 +78,29 @@ func (a *AsciidocConverter) GetAsciidocContent(src []byte, ctx converter.Documen
 	args := a.ParseArgs(ctx)
 	args = append(args, "-")
 
-	a.Cfg.Logger.Infoln("Rendering", ctx.DocumentName, " using asciidoctor args", args, "...")
+	a.Cfg.Logger.Infoln("Rendering", ctx.DocumentName, "with", binaryName, "using args", args, "...")
 
-	return internal.ExternallyRenderContent(a.Cfg, ctx, src, asciiDocBinaryName, args)
+	return internal.ExternallyRenderContent(a.Cfg, ctx, src, binaryName, args)
+}
+
+// binaryName returns the binary to use for rendering, consulting
+// markup.asciidocExt.path before probing for the default asciidoctor.
+func (a *AsciidocConverter) binaryName() string {
+	if p := a.Cfg.MarkupConfig().AsciidocExt.Path; p != "" {
+		if strings.ContainsAny(p, `/\`) {
+			a.Cfg.Logger.Errorf("markup.asciidocExt.path %q must be a binary name resolved via $PATH, not a path.", p)
+			return ""
+		}
+		if !hexec.InPath(p) {
+			a.Cfg.Logger.Errorf("markup.asciidocExt.path %q not found in $PATH.", p)
+			return ""
+		}
+		return p
+	}
+	if HasAsciiDoc() {
+		return asciiDocBinaryName
+	}
+	return ""
 }
 
 func (a *AsciidocConverter) ParseArgs(ctx converter.DocumentContext) []string {
@@ -92,6 +126,28 @@ func (a *AsciidocConverter) ParseArgs(ctx converter.DocumentContext) []string {
 		args = append(args, "-a", attributeKey+"="+attributeValue)
 	}
 
+	// Per-document attributes, e.g. from the page's front matter. These
+	// are user data, so sanitize them before they end up on a command
+	// line. Sorted for deterministic argument order.
+	attributeKeys := make([]string, 0, len(ctx.Attributes))
+	for attributeKey := range ctx.Attributes {
+		attributeKeys = append(attributeKeys, attributeKey)
+	}
+	sort.Strings(attributeKeys)
+	for _, attributeKey := range attributeKeys {
+		attributeValue := ctx.Attributes[attributeKey]
+		if asciidocext_config.DisallowedAttributes[attributeKey] {
+			a.Cfg.Logger.Errorln("Unsupported asciidoctor attribute was passed in. Attribute `" + attributeKey + "` ignored.")
+			continue
+		}
+		if !asciidocAttributeKeyRe.MatchString(attributeKey) || strings.ContainsAny(attributeValue, "\x00\n\r") {
+			a.Cfg.Logger.Errorln("Invalid asciidoctor attribute `" + attributeKey + "` ignored.")
+			continue
+		}
+
+		args = append(args, "-a", attributeKey+"="+attributeValue)
+	}
+
 	if cfg.WorkingFolderCurrent {
 		contentDir := filepath.Dir(ctx.Filename)
 		destinationDir := a.Cfg.Conf.BaseConfig().PublishDir
@@ -150,6 +206,10 @@ func (a *AsciidocConverter) ParseArgs(ctx converter.DocumentContext) []string {
 	return args
 }
 
+// asciidocAttributeKeyRe matches the attribute names accepted from the
+// document context; anything else could be misread as a CLI option.
+var asciidocAttributeKeyRe = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_-]*$`)
+
 func (a *AsciidocConverter) AppendArg(args []string, option, value, defaultValue string, allowedValues map[string]bool) []string {
 	if value != defaultValue {
 		if allowedValues[value] {
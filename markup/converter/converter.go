@@ -35,6 +35,11 @@ type ProviderConfig struct {
 	Logger    loggers.Logger
 	Exec      *hexec.Exec
 	highlight.Highlighter
+
+	// RenderCache, if set, caches converter output across builds so
+	// unchanged content skips conversion. Nil (the default) disables
+	// caching; tests that assert subprocess invocations rely on that.
+	RenderCache *RenderCache
 }
 
 func (p ProviderConfig) MarkupConfig() markup_config.Config {
@@ -73,6 +78,10 @@ func (n newConverter) Name() string {
 	return n.name
 }
 
+// FeatureEmoji is the feature to pass to Converter.Supports to check whether
+// the converter expands :emoji: shortcodes as part of the conversion.
+var FeatureEmoji = identity.StringIdentity("feature/emoji")
+
 var NopConverter = new(nopConverter)
 
 type nopConverter int
@@ -140,6 +149,11 @@ type DocumentContext struct {
 	DocumentID     string
 	DocumentName   string
 	Filename       string
+
+	// Attributes holds optional per-document attributes, e.g. from the
+	// page's front matter, passed on to converters that support them.
+	// May be nil.
+	Attributes map[string]string
 }
 
 // RenderContext holds contextual information about the content to render.
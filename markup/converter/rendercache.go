@@ -0,0 +1,62 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter
+
+import (
+	"sync"
+
+	"github.com/gohugoio/hugo/identity"
+)
+
+// RenderCache caches rendered converter output across builds. Entries are
+// keyed by the converter name, a fingerprint of the relevant configuration
+// and a hash of the source, so a change to any of them misses the cache.
+// It is safe for concurrent use. A nil *RenderCache disables caching, which
+// is the default.
+type RenderCache struct {
+	mu sync.RWMutex
+	m  map[string][]byte
+}
+
+// NewRenderCache creates a new, empty RenderCache.
+func NewRenderCache() *RenderCache {
+	return &RenderCache{m: make(map[string][]byte)}
+}
+
+// Get returns the cached output for the given converter name, config
+// fingerprint and source, if any.
+func (c *RenderCache) Get(name, configFingerprint string, src []byte) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	b, found := c.m[renderCacheKey(name, configFingerprint, src)]
+	c.mu.RUnlock()
+	return b, found
+}
+
+// Put stores b as the output for the given converter name, config
+// fingerprint and source.
+func (c *RenderCache) Put(name, configFingerprint string, src, b []byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.m[renderCacheKey(name, configFingerprint, src)] = b
+	c.mu.Unlock()
+}
+
+func renderCacheKey(name, configFingerprint string, src []byte) string {
+	return name + "/" + configFingerprint + "/" + identity.HashString(src)
+}
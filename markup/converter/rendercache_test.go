@@ -0,0 +1,49 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRenderCache(t *testing.T) {
+	c := qt.New(t)
+
+	cache := NewRenderCache()
+	src := []byte("# Heading")
+
+	_, found := cache.Get("pandoc", "a", src)
+	c.Assert(found, qt.IsFalse)
+
+	cache.Put("pandoc", "a", src, []byte("<h1>Heading</h1>"))
+	b, found := cache.Get("pandoc", "a", src)
+	c.Assert(found, qt.IsTrue)
+	c.Assert(string(b), qt.Equals, "<h1>Heading</h1>")
+
+	// Different config fingerprint, converter name or source misses.
+	_, found = cache.Get("pandoc", "b", src)
+	c.Assert(found, qt.IsFalse)
+	_, found = cache.Get("rst2html", "a", src)
+	c.Assert(found, qt.IsFalse)
+	_, found = cache.Get("pandoc", "a", []byte("# Other"))
+	c.Assert(found, qt.IsFalse)
+
+	// A nil cache is a valid no-op.
+	var nilCache *RenderCache
+	nilCache.Put("pandoc", "a", src, []byte("x"))
+	_, found = nilCache.Get("pandoc", "a", src)
+	c.Assert(found, qt.IsFalse)
+}
@@ -16,6 +16,7 @@ package goldmark
 
 import (
 	"bytes"
+	htmltemplate "html/template"
 
 	"github.com/gohugoio/hugo-goldmark-extensions/passthrough"
 	"github.com/gohugoio/hugo/markup/goldmark/hugocontext"
@@ -36,6 +37,7 @@ import (
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
 
+	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/markup/converter"
 	"github.com/gohugoio/hugo/markup/tableofcontents"
 )
@@ -52,11 +54,17 @@ type provide struct{}
 func (p provide) New(cfg converter.ProviderConfig) (converter.Provider, error) {
 	md := newMarkdown(cfg)
 
+	var footnotes *footnoteNumbering
+	if cfg.MarkupConfig().Goldmark.Extensions.FootnoteNumberingScope == "document" {
+		footnotes = newFootnoteNumbering()
+	}
+
 	return converter.NewProvider("goldmark", func(ctx converter.DocumentContext) (converter.Converter, error) {
 		return &goldmarkConverter{
-			ctx: ctx,
-			cfg: cfg,
-			md:  md,
+			ctx:       ctx,
+			cfg:       cfg,
+			md:        md,
+			footnotes: footnotes,
 			sanitizeAnchorName: func(s string) string {
 				return sanitizeAnchorNameString(s, cfg.MarkupConfig().Goldmark.Parser.AutoHeadingIDType)
 			},
@@ -71,6 +79,10 @@ type goldmarkConverter struct {
 	ctx converter.DocumentContext
 	cfg converter.ProviderConfig
 
+	// Set when footnote numbering continues across renders; nil means the
+	// default per-render numbering.
+	footnotes *footnoteNumbering
+
 	sanitizeAnchorName func(s string) string
 }
 
@@ -78,6 +90,14 @@ func (c *goldmarkConverter) SanitizeAnchorName(s string) string {
 	return c.sanitizeAnchorName(s)
 }
 
+// Supports reports whether this converter provides the given feature.
+// Emoji expansion is supported when enableEmoji is set; the extension
+// skips shortcodes inside code spans and blocks.
+func (c *goldmarkConverter) Supports(feature identity.Identity) bool {
+	return feature.IdentifierBase() == converter.FeatureEmoji.IdentifierBase() &&
+		c.cfg.Conf.EnableEmoji()
+}
+
 func newMarkdown(pcfg converter.ProviderConfig) goldmark.Markdown {
 	mcfg := pcfg.MarkupConfig()
 	cfg := mcfg.Goldmark
@@ -284,11 +304,42 @@ func (c *goldmarkConverter) Render(ctx converter.RenderContext, doc any) (conver
 		return nil, err
 	}
 
+	if heading := c.cfg.MarkupConfig().Goldmark.Extensions.FootnotesHeading; heading != "" {
+		if b := insertFootnotesHeading(buf.Bytes(), heading); b != nil {
+			buf.Reset()
+			buf.Write(b)
+		}
+	}
+
+	if c.footnotes != nil {
+		if b := c.footnotes.renumber(buf.Bytes(), c.ctx.DocumentID); b != nil {
+			buf.Reset()
+			buf.Write(b)
+		}
+	}
+
 	return renderResult{
 		ResultRender: buf,
 	}, nil
 }
 
+var footnotesDivStart = []byte(`<div class="footnotes"`)
+
+// insertFootnotesHeading inserts heading as a h2 element in front of the
+// footnotes section in b, or returns nil if b has no footnotes.
+func insertFootnotesHeading(b []byte, heading string) []byte {
+	i := bytes.Index(b, footnotesDivStart)
+	if i == -1 {
+		return nil
+	}
+	h := []byte(`<h2 class="footnotes-heading">` + htmltemplate.HTMLEscapeString(heading) + "</h2>\n")
+	bb := make([]byte, 0, len(b)+len(h))
+	bb = append(bb, b[:i]...)
+	bb = append(bb, h...)
+	bb = append(bb, b[i:]...)
+	return bb
+}
+
 func (c *goldmarkConverter) Convert(ctx converter.RenderContext) (converter.ResultRender, error) {
 	parseResult, err := c.Parse(ctx)
 	if err != nil {
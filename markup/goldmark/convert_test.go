@@ -33,6 +33,7 @@ import (
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/common/maps"
 
+	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/markup/converter"
 
 	qt "github.com/frankban/quicktest"
@@ -741,3 +742,112 @@ escapedSpace=true
 
 	c.Assert(got, qt.Contains, "<p>私は太郎です。\nプログラミングが好きです。運動が苦手です。</p>\n")
 }
+
+func TestConvertFootnotesHeading(t *testing.T) {
+	c := qt.New(t)
+
+	content := `
+Some text.[^1]
+
+[^1]: A footnote.
+`
+
+	cfg := config.FromTOMLConfigString(`
+[markup]
+[markup.goldmark]
+[markup.goldmark.extensions]
+footnotesHeading = 'Notes'
+`)
+
+	b := convert(c, testconfig.GetTestConfig(nil, cfg), content)
+	got := string(b.Bytes())
+
+	c.Assert(got, qt.Contains, "<h2 class=\"footnotes-heading\">Notes</h2>\n<div class=\"footnotes\"")
+
+	// No footnotes, no heading.
+	b = convert(c, testconfig.GetTestConfig(nil, cfg), "No footnotes here.")
+	c.Assert(string(b.Bytes()), qt.Not(qt.Contains), "footnotes-heading")
+
+	// Default is no heading.
+	b = convert(c, testconfig.GetTestConfig(nil, nil), content)
+	got = string(b.Bytes())
+	c.Assert(got, qt.Contains, "<div class=\"footnotes\"")
+	c.Assert(got, qt.Not(qt.Contains), "footnotes-heading")
+}
+
+func TestSupportsEmoji(t *testing.T) {
+	c := qt.New(t)
+
+	newConverter := func(conf config.AllProvider) converter.Converter {
+		p, err := goldmark.Provider.New(converter.ProviderConfig{
+			Logger: loggers.NewDefault(),
+			Conf:   conf,
+		})
+		c.Assert(err, qt.IsNil)
+		conv, err := p.New(converter.DocumentContext{})
+		c.Assert(err, qt.IsNil)
+		return conv
+	}
+
+	type featureSupporter interface {
+		Supports(feature identity.Identity) bool
+	}
+
+	cfg := config.New()
+	cfg.Set("enableEmoji", true)
+	conv := newConverter(testconfig.GetTestConfig(nil, cfg))
+	c.Assert(conv.(featureSupporter).Supports(converter.FeatureEmoji), qt.Equals, true)
+
+	conv = newConverter(testconfig.GetTestConfig(nil, nil))
+	c.Assert(conv.(featureSupporter).Supports(converter.FeatureEmoji), qt.Equals, false)
+}
+
+func TestFootnoteNumberingScope(t *testing.T) {
+	c := qt.New(t)
+
+	newProvider := func(scope string) converter.Provider {
+		cfg := config.FromTOMLConfigString(fmt.Sprintf(`
+[markup.goldmark.extensions]
+footnoteNumberingScope = %q
+`, scope))
+		p, err := goldmark.Provider.New(converter.ProviderConfig{
+			Logger: loggers.NewDefault(),
+			Conf:   testconfig.GetTestConfig(nil, cfg),
+		})
+		c.Assert(err, qt.IsNil)
+		return p
+	}
+
+	render := func(p converter.Provider, docID, content string) string {
+		conv, err := p.New(converter.DocumentContext{DocumentID: docID})
+		c.Assert(err, qt.IsNil)
+		b, err := conv.Convert(converter.RenderContext{
+			Src:         []byte(content),
+			GetRenderer: func(hooks.RendererType, any) any { return nil },
+		})
+		c.Assert(err, qt.IsNil)
+		return string(b.Bytes())
+	}
+
+	p := newProvider("document")
+
+	got := render(p, "doc1", "First.[^a]\n\n[^a]: Note A.\n")
+	c.Assert(got, qt.Contains, `id="fnref:1"`)
+
+	// The next fragment of the same document continues the numbering.
+	got = render(p, "doc1", "Second.[^b]\n\n[^b]: Note B.\n")
+	c.Assert(got, qt.Contains, `id="fnref:2"`)
+	c.Assert(got, qt.Contains, `href="#fn:2"`)
+	c.Assert(got, qt.Contains, `<li id="fn:2"`)
+	c.Assert(got, qt.Contains, `role="doc-noteref">2</a>`)
+
+	// A different document starts at 1 again.
+	got = render(p, "doc2", "Other.[^c]\n\n[^c]: Note C.\n")
+	c.Assert(got, qt.Contains, `id="fnref:1"`)
+
+	// The default restarts for every render.
+	p = newProvider("render")
+	_ = render(p, "doc1", "First.[^a]\n\n[^a]: Note A.\n")
+	got = render(p, "doc1", "Second.[^b]\n\n[^b]: Note B.\n")
+	c.Assert(got, qt.Contains, `id="fnref:1"`)
+}
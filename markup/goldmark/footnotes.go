@@ -0,0 +1,81 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldmark
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// footnoteNumbering continues footnote numbering across renders that share
+// a document ID, so fragments of one document don't restart at 1 (see
+// the footnoteNumberingScope setting). One instance is shared by all
+// converters of a provider.
+type footnoteNumbering struct {
+	mu      sync.Mutex
+	offsets map[string]int
+}
+
+func newFootnoteNumbering() *footnoteNumbering {
+	return &footnoteNumbering{offsets: make(map[string]int)}
+}
+
+var (
+	// The id and href attributes of the footnote refs, backrefs and list
+	// items, e.g. id="fnref:1", id="fnref1:1", href="#fn:1".
+	footnoteIDRe = regexp.MustCompile(`((?:id="|href="#)fn(?:ref\d*)?:)(\d+)`)
+
+	// The visible number of a footnote ref, e.g. >1 in
+	// <a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a>.
+	footnoteRefTextRe = regexp.MustCompile(`(class="footnote-ref"[^>]*>)(\d+)`)
+
+	footnoteItemID = []byte(`<li id="fn:`)
+)
+
+// renumber shifts the footnote numbers in b by the count of footnotes
+// rendered earlier for documentID and records this render's count. It
+// returns nil if b needs no rewriting.
+func (f *footnoteNumbering) renumber(b []byte, documentID string) []byte {
+	count := bytes.Count(b, footnoteItemID)
+	if count == 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	offset := f.offsets[documentID]
+	f.offsets[documentID] = offset + count
+	f.mu.Unlock()
+
+	if offset == 0 {
+		return nil
+	}
+
+	// Both patterns end in the number to shift.
+	shift := func(m []byte) []byte {
+		i := len(m)
+		for i > 0 && m[i-1] >= '0' && m[i-1] <= '9' {
+			i--
+		}
+		n, err := strconv.Atoi(string(m[i:]))
+		if err != nil {
+			return m
+		}
+		return append(m[:i:i], strconv.Itoa(n+offset)...)
+	}
+
+	b = footnoteIDRe.ReplaceAllFunc(b, shift)
+	return footnoteRefTextRe.ReplaceAllFunc(b, shift)
+}
@@ -109,8 +109,21 @@ func (h LinkRenderHook) IsEnableDefault() bool {
 }
 
 type Extensions struct {
-	Typographer    Typographer
-	Footnote       bool
+	Typographer Typographer
+	Footnote    bool
+
+	// FootnotesHeading, if set, is rendered as a h2 heading in front of the
+	// footnotes section. Only used when the Footnote extension is enabled.
+	// Empty (the default) leaves the output unchanged.
+	FootnotesHeading string
+
+	// FootnoteNumberingScope controls footnote numbering when a document is
+	// rendered in several fragments: "render" (the default) restarts the
+	// numbering for every render, "document" continues it across renders
+	// sharing a document ID. Only used when the Footnote extension is
+	// enabled.
+	FootnoteNumberingScope string
+
 	DefinitionList bool
 	Passthrough    Passthrough
 
@@ -3,10 +3,13 @@ package internal
 import (
 	"bytes"
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/gohugoio/hugo/common/collections"
 	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/markup/converter"
 )
 
@@ -21,6 +24,20 @@ func ExternallyRenderContent(
 		panic(fmt.Sprintf("should be no slash in %q", binaryName))
 	}
 
+	// The binary name and its arguments is all that determines the output
+	// for a given source.
+	fingerprint := identity.HashString(args)
+	if b, found := cfg.RenderCache.Get(binaryName, fingerprint, content); found {
+		return b, nil
+	}
+
+	limit := 0
+	if cfg.Conf != nil {
+		limit = cfg.MarkupConfig().ExternalHelperConcurrency
+	}
+	externalHelpers.acquire(limit)
+	defer externalHelpers.release()
+
 	argsv := collections.StringSliceToInterfaceSlice(args)
 
 	var out, cmderr bytes.Buffer
@@ -52,7 +69,12 @@ func ExternallyRenderContent(
 		logger.Errorf("%s rendering %s: %v", binaryName, ctx.DocumentName, err)
 	}
 
-	return normalizeExternalHelperLineFeeds(out.Bytes()), nil
+	b := normalizeExternalHelperLineFeeds(out.Bytes())
+	if err == nil {
+		cfg.RenderCache.Put(binaryName, fingerprint, content, b)
+	}
+
+	return b, nil
 }
 
 // Strips carriage returns from third-party / external processes (useful for Windows)
@@ -60,6 +82,44 @@ func normalizeExternalHelperLineFeeds(content []byte) []byte {
 	return bytes.Replace(content, []byte("\r"), []byte(""), -1)
 }
 
+// externalHelpers limits how many external helper processes run
+// concurrently, shared across all formats in a build, so a large site
+// doesn't exhaust file descriptors or memory by spawning one subprocess
+// per page. Renders above the limit wait for a slot.
+var externalHelpers = newConcurrencyLimiter()
+
+type concurrencyLimiter struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	n    int
+}
+
+func newConcurrencyLimiter() *concurrencyLimiter {
+	l := &concurrencyLimiter{}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until a slot is free. A limit <= 0 means GOMAXPROCS.
+func (l *concurrencyLimiter) acquire(limit int) {
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	l.mu.Lock()
+	for l.n >= limit {
+		l.cond.Wait()
+	}
+	l.n++
+	l.mu.Unlock()
+}
+
+func (l *concurrencyLimiter) release() {
+	l.mu.Lock()
+	l.n--
+	l.cond.Broadcast()
+	l.mu.Unlock()
+}
+
 var pythonBinaryCandidates = []string{"python", "python.exe"}
 
 func GetPythonBinaryAndExecPath() (string, string) {
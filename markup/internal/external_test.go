@@ -0,0 +1,55 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	c := qt.New(t)
+
+	const limit = 3
+	l := newConcurrencyLimiter()
+
+	var (
+		wg          sync.WaitGroup
+		running     atomic.Int64
+		maxObserved atomic.Int64
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.acquire(limit)
+			defer l.release()
+			n := running.Add(1)
+			for {
+				max := maxObserved.Load()
+				if n <= max || maxObserved.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	c.Assert(maxObserved.Load() <= limit, qt.IsTrue)
+	c.Assert(l.n, qt.Equals, 0)
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import "golang.org/x/net/html"
+
+// WalkHTML walks the tree rooted at n in depth-first pre-order: n first,
+// then each child subtree in document order. visit is called for every node;
+// returning true stops the walk. WalkHTML reports whether the walk was
+// stopped. It is safe for visit to detach the current node from its parent.
+func WalkHTML(n *html.Node, visit func(n *html.Node) bool) bool {
+	if visit(n) {
+		return true
+	}
+	for c := n.FirstChild; c != nil; {
+		// Fetch the sibling up front so visit can remove c.
+		next := c.NextSibling
+		if WalkHTML(c, visit) {
+			return true
+		}
+		c = next
+	}
+	return false
+}
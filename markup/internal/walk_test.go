@@ -0,0 +1,68 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"golang.org/x/net/html"
+)
+
+func TestWalkHTML(t *testing.T) {
+	c := qt.New(t)
+
+	elem := func(name string, children ...*html.Node) *html.Node {
+		n := &html.Node{Type: html.ElementNode, Data: name}
+		for _, child := range children {
+			n.AppendChild(child)
+		}
+		return n
+	}
+
+	// <a><b><c/><d/></b><e/></a>
+	tree := elem("a",
+		elem("b", elem("c"), elem("d")),
+		elem("e"),
+	)
+
+	var visited []string
+	stopped := WalkHTML(tree, func(n *html.Node) bool {
+		visited = append(visited, n.Data)
+		return false
+	})
+	c.Assert(stopped, qt.IsFalse)
+	c.Assert(strings.Join(visited, " "), qt.Equals, "a b c d e")
+
+	visited = nil
+	stopped = WalkHTML(tree, func(n *html.Node) bool {
+		visited = append(visited, n.Data)
+		return n.Data == "d"
+	})
+	c.Assert(stopped, qt.IsTrue)
+	c.Assert(strings.Join(visited, " "), qt.Equals, "a b c d")
+
+	// Detaching the visited node must not derail the walk.
+	visited = nil
+	stopped = WalkHTML(tree, func(n *html.Node) bool {
+		visited = append(visited, n.Data)
+		if n.Data == "b" {
+			n.Parent.RemoveChild(n)
+		}
+		return false
+	})
+	c.Assert(stopped, qt.IsFalse)
+	c.Assert(strings.Join(visited, " "), qt.Equals, "a b c d e")
+}
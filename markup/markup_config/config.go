@@ -19,6 +19,8 @@ import (
 	"github.com/gohugoio/hugo/markup/asciidocext/asciidocext_config"
 	"github.com/gohugoio/hugo/markup/goldmark/goldmark_config"
 	"github.com/gohugoio/hugo/markup/highlight"
+	"github.com/gohugoio/hugo/markup/pandoc/pandoc_config"
+	"github.com/gohugoio/hugo/markup/rst/rst_config"
 	"github.com/gohugoio/hugo/markup/tableofcontents"
 	"github.com/mitchellh/mapstructure"
 )
@@ -39,11 +41,31 @@ type Config struct {
 
 	// Configuration for the Asciidoc external markdown engine.
 	AsciidocExt asciidocext_config.Config
+
+	// Configuration for the reStructuredText external engine.
+	RST rst_config.Config
+
+	// Configuration for the Pandoc external engine.
+	Pandoc pandoc_config.Config
+
+	// ExternalHelperConcurrency caps how many external helper processes
+	// (asciidoctor, pandoc, rst2html) can run at the same time. The limit is
+	// shared across all formats in a build; renders above the limit wait.
+	// Default 0 means the number of CPUs.
+	ExternalHelperConcurrency int
 }
 
 func Decode(cfg config.Provider) (conf Config, err error) {
 	conf = Default
 
+	// Decoding merges map values into any pre-existing map, so give conf
+	// its own copy to avoid mutating the map shared with Default.
+	asciidocAttributes := make(map[string]string, len(conf.AsciidocExt.Attributes))
+	for k, v := range conf.AsciidocExt.Attributes {
+		asciidocAttributes[k] = v
+	}
+	conf.AsciidocExt.Attributes = asciidocAttributes
+
 	m := cfg.GetStringMap("markup")
 	if m == nil {
 		return
@@ -61,6 +83,10 @@ func Decode(cfg config.Provider) (conf Config, err error) {
 		return
 	}
 
+	if err = conf.Pandoc.Validate(); err != nil {
+		return
+	}
+
 	return
 }
 
@@ -105,4 +131,6 @@ var Default = Config{
 
 	Goldmark:    goldmark_config.Default,
 	AsciidocExt: asciidocext_config.Default,
+	RST:         rst_config.Default,
+	Pandoc:      pandoc_config.Default,
 }
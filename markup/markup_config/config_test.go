@@ -85,3 +85,35 @@ func TestConfig(t *testing.T) {
 		c.Assert(conf.Goldmark.Extensions.Typographer.Ellipsis, qt.Equals, "&hellip;")
 	})
 }
+
+func TestConfigPandocFrom(t *testing.T) {
+	c := qt.New(t)
+
+	v := config.New()
+	v.Set("markup", map[string]any{
+		"pandoc": map[string]any{
+			"from": "rst",
+		},
+	})
+	conf, err := Decode(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Pandoc.From, qt.Equals, "rst")
+
+	// Extension modifiers are allowed.
+	v.Set("markup", map[string]any{
+		"pandoc": map[string]any{
+			"from": "markdown+smart",
+		},
+	})
+	_, err = Decode(v)
+	c.Assert(err, qt.IsNil)
+
+	// An unknown input format is an error.
+	v.Set("markup", map[string]any{
+		"pandoc": map[string]any{
+			"from": "wordperfect",
+		},
+	})
+	_, err = Decode(v)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
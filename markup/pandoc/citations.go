@@ -0,0 +1,73 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pandoc
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gohugoio/hugo/markup/internal"
+	"golang.org/x/net/html"
+)
+
+// Citation holds one entry from a bibliography rendered by pandoc's
+// citeproc filter.
+type Citation struct {
+	// The citation ID, e.g. "Doe2020".
+	ID string
+
+	// The rendered bibliography entry as HTML.
+	Rendered string
+}
+
+// extractCitations collects the citation entries from the "#refs" div that
+// citeproc emits, one per child element with a "ref-" prefixed id. The refs
+// div itself is left in place in the rendered output; the structured data
+// is additive. It returns nil when the output holds no bibliography.
+func extractCitations(src []byte) ([]Citation, error) {
+	if !bytes.Contains(src, []byte(`id="refs"`)) {
+		return nil, nil
+	}
+	node, err := html.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	var refs *html.Node
+	found := internal.WalkHTML(node, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && attr(n, "id") == "refs" {
+			refs = n
+			return true
+		}
+		return false
+	})
+	if !found {
+		return nil, nil
+	}
+
+	var citations []Citation
+	for c := refs.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		id := attr(c, "id")
+		if !strings.HasPrefix(id, "ref-") {
+			continue
+		}
+		citations = append(citations, Citation{
+			ID:       strings.TrimPrefix(id, "ref-"),
+			Rendered: strings.TrimSpace(nodeContent(c)),
+		})
+	}
+	return citations, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pandoc
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestExtractCitations(t *testing.T) {
+	c := qt.New(t)
+
+	src := `<p>Text <span class="citation" data-cites="Doe2020">(Doe 2020)</span>.</p>
+<div id="refs" class="references csl-bib-body hanging-indent" role="list">
+<div id="ref-Doe2020" class="csl-entry" role="listitem">
+Doe, Jane. 2020. <em>A Book</em>.
+</div>
+<div id="ref-Smith2021" class="csl-entry" role="listitem">
+Smith, John. 2021. <em>Another Book</em>.
+</div>
+</div>`
+
+	citations, err := extractCitations([]byte(src))
+	c.Assert(err, qt.IsNil)
+	c.Assert(citations, qt.HasLen, 2)
+	c.Assert(citations[0].ID, qt.Equals, "Doe2020")
+	c.Assert(citations[0].Rendered, qt.Equals, "Doe, Jane. 2020. <em>A Book</em>.")
+	c.Assert(citations[1].ID, qt.Equals, "Smith2021")
+
+	// No bibliography is not an error.
+	citations, err = extractCitations([]byte("<p>No refs here.</p>"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(citations, qt.IsNil)
+}
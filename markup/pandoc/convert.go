@@ -15,12 +15,19 @@
 package pandoc
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/htesting"
 	"github.com/gohugoio/hugo/identity"
 
 	"github.com/gohugoio/hugo/markup/converter"
 	"github.com/gohugoio/hugo/markup/internal"
+	"github.com/gohugoio/hugo/markup/pandoc/pandoc_config"
+	"github.com/gohugoio/hugo/markup/tableofcontents"
 )
 
 // Provider is the package entry point.
@@ -42,12 +49,43 @@ type pandocConverter struct {
 	cfg converter.ProviderConfig
 }
 
+type pandocResult struct {
+	converter.ResultRender
+	toc       *tableofcontents.Fragments
+	citations []Citation
+}
+
+func (r pandocResult) TableOfContents() *tableofcontents.Fragments {
+	return r.toc
+}
+
+// Citations returns the bibliography entries rendered by citeproc, if any.
+// The entries also remain inline in the rendered output.
+func (r pandocResult) Citations() []Citation {
+	return r.citations
+}
+
 func (c *pandocConverter) Convert(ctx converter.RenderContext) (converter.ResultRender, error) {
 	b, err := c.getPandocContent(ctx.Src, c.ctx)
 	if err != nil {
 		return nil, err
 	}
-	return converter.Bytes(b), nil
+	content, toc, err := extractTOC(b)
+	if err != nil {
+		return nil, err
+	}
+	citations, err := extractCitations(content)
+	if err != nil {
+		return nil, err
+	}
+	if toc == nil && citations == nil {
+		return converter.Bytes(b), nil
+	}
+	return pandocResult{
+		ResultRender: converter.Bytes(content),
+		toc:          toc,
+		citations:    citations,
+	}, nil
 }
 
 func (c *pandocConverter) Supports(feature identity.Identity) bool {
@@ -63,10 +101,61 @@ func (c *pandocConverter) getPandocContent(src []byte, ctx converter.DocumentCon
 			"                 Leaving pandoc content unrendered.")
 		return src, nil
 	}
-	args := []string{"--mathjax"}
+	args, err := c.pandocArgs(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return internal.ExternallyRenderContent(c.cfg, ctx, src, binaryName, args)
 }
 
+// CommandLine returns the resolved pandoc binary and the arguments Convert
+// would invoke it with for the given document, without running the
+// subprocess. This is useful for verifying e.g. the security.exec
+// configuration before a build. It returns an error if pandoc is not found
+// in $PATH.
+func (c *pandocConverter) CommandLine(ctx converter.DocumentContext) (string, []string, error) {
+	binaryName := getPandocBinaryName()
+	if binaryName == "" {
+		return "", nil, errors.New("pandoc not found in $PATH")
+	}
+	args, err := c.pandocArgs(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return binaryName, args, nil
+}
+
+// pandocArgs returns the arguments to pass to pandoc for the given document.
+func (c *pandocConverter) pandocArgs(ctx converter.DocumentContext) ([]string, error) {
+	args := []string{"--mathjax"}
+	conf := c.cfg.MarkupConfig().Pandoc
+	from := conf.From
+	if from == "" {
+		from = "markdown"
+	}
+	// Strip raw HTML blocks from the source unless markup.pandoc.unsafe is
+	// set, mirroring the Goldmark unsafe flag. The raw_html extension only
+	// exists for the markdown reader family, so for other input formats the
+	// unsafe toggle is a no-op.
+	switch base := pandoc_config.BaseInputFormat(from); {
+	case strings.HasPrefix(base, "markdown"), base == "commonmark", base == "gfm":
+		if conf.Unsafe {
+			from += "+raw_html"
+		} else {
+			from += "-raw_html"
+		}
+	}
+	args = append(args, "--from", from)
+	// Lua filters compose, so the configured order is preserved.
+	for _, filter := range conf.LuaFilters {
+		if _, err := os.Stat(filter); err != nil {
+			return nil, fmt.Errorf("markup.pandoc.luaFilters: can't stat Lua filter %q: %w", filter, err)
+		}
+		args = append(args, "--lua-filter", filter)
+	}
+	return args, nil
+}
+
 const pandocBinary = "pandoc"
 
 func getPandocBinaryName() string {
@@ -11,34 +11,144 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package pandoc
+package pandoc_test
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/config/security"
+	"github.com/gohugoio/hugo/config/testconfig"
+	"github.com/gohugoio/hugo/markup/markup_config"
 
 	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/pandoc"
 
 	qt "github.com/frankban/quicktest"
 )
 
-func TestConvert(t *testing.T) {
-	if !Supports() {
-		t.Skip("pandoc not installed")
-	}
-	c := qt.New(t)
+func newTestConverter(c *qt.C, mconf markup_config.Config) converter.Converter {
 	sc := security.DefaultConfig
 	var err error
 	sc.Exec.Allow, err = security.NewWhitelist("pandoc")
 	c.Assert(err, qt.IsNil)
-	p, err := Provider.New(converter.ProviderConfig{Exec: hexec.New(sc), Logger: loggers.NewDefault()})
+	p, err := pandoc.Provider.New(converter.ProviderConfig{
+		Conf:   testconfig.GetTestConfigSectionFromStruct("markup", mconf),
+		Exec:   hexec.New(sc),
+		Logger: loggers.NewDefault(),
+	})
 	c.Assert(err, qt.IsNil)
 	conv, err := p.New(converter.DocumentContext{})
 	c.Assert(err, qt.IsNil)
+	return conv
+}
+
+func TestConvert(t *testing.T) {
+	if !pandoc.Supports() {
+		t.Skip("pandoc not installed")
+	}
+	c := qt.New(t)
+	conv := newTestConverter(c, markup_config.Default)
 	b, err := conv.Convert(converter.RenderContext{Src: []byte("testContent")})
 	c.Assert(err, qt.IsNil)
 	c.Assert(string(b.Bytes()), qt.Equals, "<p>testContent</p>\n")
 }
+
+func TestConvertRawHTML(t *testing.T) {
+	if !pandoc.Supports() {
+		t.Skip("pandoc not installed")
+	}
+	c := qt.New(t)
+	src := []byte("<script>alert(1)</script>\n\ntestContent\n")
+
+	// Default: raw HTML is stripped.
+	conv := newTestConverter(c, markup_config.Default)
+	b, err := conv.Convert(converter.RenderContext{Src: src})
+	c.Assert(err, qt.IsNil)
+	c.Assert(strings.Contains(string(b.Bytes()), "<script>"), qt.IsFalse)
+
+	// Unsafe: raw HTML passes through.
+	mconf := markup_config.Default
+	mconf.Pandoc.Unsafe = true
+	conv = newTestConverter(c, mconf)
+	b, err = conv.Convert(converter.RenderContext{Src: src})
+	c.Assert(err, qt.IsNil)
+	c.Assert(strings.Contains(string(b.Bytes()), "<script>"), qt.IsTrue)
+}
+
+func TestCommandLine(t *testing.T) {
+	if !pandoc.Supports() {
+		t.Skip("pandoc not installed")
+	}
+	c := qt.New(t)
+	conv := newTestConverter(c, markup_config.Default)
+	binary, args, err := conv.(interface {
+		CommandLine(ctx converter.DocumentContext) (string, []string, error)
+	}).CommandLine(converter.DocumentContext{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(binary, qt.Equals, "pandoc")
+	c.Assert(args, qt.DeepEquals, []string{"--mathjax", "--from", "markdown-raw_html"})
+}
+
+func TestCommandLineFrom(t *testing.T) {
+	if !pandoc.Supports() {
+		t.Skip("pandoc not installed")
+	}
+	c := qt.New(t)
+	mconf := markup_config.Default
+	mconf.Pandoc.From = "latex"
+	conv := newTestConverter(c, mconf)
+	_, args, err := conv.(interface {
+		CommandLine(ctx converter.DocumentContext) (string, []string, error)
+	}).CommandLine(converter.DocumentContext{})
+	c.Assert(err, qt.IsNil)
+	// No raw_html modifier outside the markdown reader family.
+	c.Assert(args, qt.DeepEquals, []string{"--mathjax", "--from", "latex"})
+
+	mconf.Pandoc.From = "gfm"
+	conv = newTestConverter(c, mconf)
+	_, args, err = conv.(interface {
+		CommandLine(ctx converter.DocumentContext) (string, []string, error)
+	}).CommandLine(converter.DocumentContext{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(args, qt.DeepEquals, []string{"--mathjax", "--from", "gfm-raw_html"})
+}
+
+func TestLuaFilters(t *testing.T) {
+	if !pandoc.Supports() {
+		t.Skip("pandoc not installed")
+	}
+	c := qt.New(t)
+
+	dir := t.TempDir()
+	upper := filepath.Join(dir, "upper.lua")
+	c.Assert(os.WriteFile(upper, []byte("function Str(el)\n  return pandoc.Str(el.text:upper())\nend\n"), 0o644), qt.IsNil)
+	noop := filepath.Join(dir, "noop.lua")
+	c.Assert(os.WriteFile(noop, []byte("-- no-op\n"), 0o644), qt.IsNil)
+
+	mconf := markup_config.Default
+	mconf.Pandoc.LuaFilters = []string{upper, noop}
+	conv := newTestConverter(c, mconf)
+
+	// The configured order is preserved.
+	_, args, err := conv.(interface {
+		CommandLine(ctx converter.DocumentContext) (string, []string, error)
+	}).CommandLine(converter.DocumentContext{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(args, qt.DeepEquals, []string{"--mathjax", "--from", "markdown-raw_html", "--lua-filter", upper, "--lua-filter", noop})
+
+	b, err := conv.Convert(converter.RenderContext{Src: []byte("testContent")})
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b.Bytes()), qt.Equals, "<p>TESTCONTENT</p>\n")
+
+	// A missing filter is a clear error.
+	mconf.Pandoc.LuaFilters = []string{filepath.Join(dir, "missing.lua")}
+	conv = newTestConverter(c, mconf)
+	_, err = conv.Convert(converter.RenderContext{Src: []byte("testContent")})
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(err.Error(), qt.Contains, "missing.lua")
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pandoc_config holds Pandoc related configuration.
+package pandoc_config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Default holds Hugo's default Pandoc configuration.
+var Default = Config{}
+
+// Config configures the Pandoc external helper.
+type Config struct {
+	// Unsafe enables Pandoc's raw_html reader extension, letting raw HTML
+	// blocks in the source pass through to the output. Mirrors the Goldmark
+	// unsafe flag and carries the same security implication: only enable it
+	// for trusted content. Default is false, which strips raw HTML.
+	Unsafe bool
+
+	// From sets Pandoc's input format (the -f/--from option), so users can
+	// author in e.g. reStructuredText or LaTeX and let Pandoc convert it.
+	// The value may carry Pandoc extension modifiers, e.g. "markdown+smart".
+	// Default is "", which means Pandoc's extended markdown.
+	From string
+
+	// LuaFilters holds paths to Lua filter scripts passed to Pandoc with
+	// --lua-filter, in order, since filters compose. Each path must exist
+	// when rendering. Note that the filters run with the privileges of the
+	// pandoc process, so only use filters you trust. Default is empty.
+	LuaFilters []string
+}
+
+// KnownInputFormats holds the Pandoc input formats accepted in From,
+// matching Pandoc's own reader list closely enough to catch typos at config
+// load time.
+var KnownInputFormats = map[string]bool{
+	"commonmark":        true,
+	"creole":            true,
+	"docbook":           true,
+	"docx":              true,
+	"epub":              true,
+	"gfm":               true,
+	"haddock":           true,
+	"html":              true,
+	"jats":              true,
+	"json":              true,
+	"latex":             true,
+	"man":               true,
+	"markdown":          true,
+	"markdown_github":   true,
+	"markdown_mmd":      true,
+	"markdown_phpextra": true,
+	"markdown_strict":   true,
+	"mediawiki":         true,
+	"muse":              true,
+	"odt":               true,
+	"opml":              true,
+	"org":               true,
+	"rst":               true,
+	"rtf":               true,
+	"t2t":               true,
+	"textile":           true,
+	"tikiwiki":          true,
+	"twiki":             true,
+	"typst":             true,
+	"vimwiki":           true,
+}
+
+// Validate checks that From, when set, names a known Pandoc input format.
+// Extension modifiers, e.g. the "+smart" in "markdown+smart", are allowed
+// and not checked.
+func (c Config) Validate() error {
+	if base := BaseInputFormat(c.From); base != "" && !KnownInputFormats[base] {
+		return fmt.Errorf("markup.pandoc.from: unknown pandoc input format %q", base)
+	}
+	return nil
+}
+
+// BaseInputFormat returns the input format without any extension modifiers,
+// e.g. "markdown" for "markdown+smart-raw_html".
+func BaseInputFormat(from string) string {
+	if i := strings.IndexAny(from, "+-"); i != -1 {
+		return from[:i]
+	}
+	return from
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pandoc
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gohugoio/hugo/markup/internal"
+	"github.com/gohugoio/hugo/markup/tableofcontents"
+	"golang.org/x/net/html"
+)
+
+// extractTOC extracts the table of contents from the given rendered HTML.
+// It returns the HTML without the TOC element and the parsed TOC data; the
+// TOC is nil, not an error, when the output holds none.
+func extractTOC(src []byte) ([]byte, *tableofcontents.Fragments, error) {
+	node, err := html.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, nil, err
+	}
+	var toc *tableofcontents.Fragments
+	found := internal.WalkHTML(node, func(n *html.Node) bool {
+		if !isTOCNode(n) {
+			return false
+		}
+		toc = parseTOC(n)
+		n.Parent.RemoveChild(n)
+		return true
+	})
+	if !found {
+		return src, nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, node); err != nil {
+		return nil, nil, err
+	}
+	// ltrim <html><head></head><body> and rtrim </body></html> which are added by html.Render
+	res := buf.Bytes()[25:]
+	res = res[:len(res)-14]
+	return res, toc, nil
+}
+
+// isTOCNode reports whether n holds the rendered table of contents. The
+// primary form is pandoc's <nav id="TOC">, but templates and newer pandoc
+// variants may emit a <div> with a toc class or mark the element with
+// role="doc-toc".
+func isTOCNode(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch {
+	case n.Data == "nav" && attr(n, "id") == "TOC":
+		return true
+	case attr(n, "role") == "doc-toc":
+		return true
+	case n.Data == "div" && hasClass(n, "toc"):
+		return true
+	}
+	return false
+}
+
+// parseTOC returns a TOC root from the given toc Node. Each top level list
+// item becomes its own row; nested lists nest below it.
+func parseTOC(doc *html.Node) *tableofcontents.Fragments {
+	var (
+		toc tableofcontents.Builder
+		row = -1
+	)
+	internal.WalkHTML(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || n.Data != "li" {
+			return false
+		}
+		level := listLevel(doc, n)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data != "a" {
+				continue
+			}
+			if level == 1 {
+				row++
+			}
+			toc.AddAt(&tableofcontents.Heading{
+				Title: nodeContent(c),
+				ID:    strings.TrimPrefix(attr(c, "href"), "#"),
+				Level: level,
+			}, row, level-1)
+		}
+		return false
+	})
+	return toc.Build()
+}
+
+// listLevel returns how deeply n is nested in lists below root: 1 for an
+// item in the top level list, 2 for one in a list nested in that, and so on.
+func listLevel(root, n *html.Node) int {
+	level := 0
+	for p := n.Parent; p != nil && p != root; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "ul" {
+			level++
+		}
+	}
+	return level
+}
+
+func attr(node *html.Node, key string) string {
+	for _, a := range node.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(node *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(node, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeContent(node *html.Node) string {
+	var buf bytes.Buffer
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		html.Render(&buf, c)
+	}
+	return buf.String()
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pandoc
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const tocList = `<ul>
+<li><a href="#first">First</a>
+<ul>
+<li><a href="#nested">Nested</a></li>
+</ul></li>
+<li><a href="#second">Second</a></li>
+</ul>`
+
+func TestExtractTOC(t *testing.T) {
+	c := qt.New(t)
+
+	for _, variant := range []string{
+		// Primary pandoc form.
+		`<nav id="TOC" role="doc-toc">` + tocList + `</nav>`,
+		// Div-based variants.
+		`<div class="toc">` + tocList + `</div>`,
+		`<div class="toc wide">` + tocList + `</div>`,
+		`<section role="doc-toc">` + tocList + `</section>`,
+	} {
+		src := variant + "\n<h1 id=\"first\">First</h1>\n<p>Text.</p>"
+		content, toc, err := extractTOC([]byte(src))
+		c.Assert(err, qt.IsNil, qt.Commentf(variant))
+		c.Assert(toc, qt.IsNotNil, qt.Commentf(variant))
+
+		headings := toc.Headings
+		c.Assert(len(headings), qt.Equals, 2, qt.Commentf(variant))
+		c.Assert(headings[0].ID, qt.Equals, "first")
+		c.Assert(headings[0].Title, qt.Equals, "First")
+		c.Assert(headings[0].Headings[0].ID, qt.Equals, "nested")
+		c.Assert(headings[1].ID, qt.Equals, "second")
+
+		s := string(content)
+		c.Assert(strings.Contains(s, "doc-toc"), qt.IsFalse, qt.Commentf(variant))
+		c.Assert(strings.Contains(s, `class="toc`), qt.IsFalse, qt.Commentf(variant))
+		c.Assert(strings.Contains(s, "<h1"), qt.IsTrue, qt.Commentf(variant))
+	}
+
+	// No TOC is not an error.
+	src := []byte("<h1 id=\"only\">Only</h1>")
+	content, toc, err := extractTOC(src)
+	c.Assert(err, qt.IsNil)
+	c.Assert(toc, qt.IsNil)
+	c.Assert(string(content), qt.Equals, string(src))
+}
@@ -17,6 +17,7 @@ package rst
 import (
 	"bytes"
 	"runtime"
+	"strings"
 
 	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/htesting"
@@ -62,7 +63,7 @@ func (c *rstConverter) Supports(feature identity.Identity) bool {
 // to convert reStructuredText content to HTML.
 func (c *rstConverter) getRstContent(src []byte, ctx converter.DocumentContext) ([]byte, error) {
 	logger := c.cfg.Logger
-	binaryName, binaryPath := getRstBinaryNameAndPath()
+	binaryName, binaryPath := c.getBinaryNameAndPath()
 
 	if binaryName == "" {
 		logger.Println("rst2html / rst2html.py not found in $PATH: Please install.\n",
@@ -111,6 +112,23 @@ func (c *rstConverter) getRstContent(src []byte, ctx converter.DocumentContext)
 
 var rst2Binaries = []string{"rst2html", "rst2html.py"}
 
+// getBinaryNameAndPath returns the binary to use for rendering, consulting
+// markup.rst.path before probing for the default candidates.
+func (c *rstConverter) getBinaryNameAndPath() (string, string) {
+	if p := c.cfg.MarkupConfig().RST.Path; p != "" {
+		if strings.ContainsAny(p, `/\`) {
+			c.cfg.Logger.Errorf("markup.rst.path %q must be a binary name resolved via $PATH, not a path.", p)
+			return "", ""
+		}
+		if pth := hexec.LookPath(p); pth != "" {
+			return p, pth
+		}
+		c.cfg.Logger.Errorf("markup.rst.path %q not found in $PATH.", p)
+		return "", ""
+	}
+	return getRstBinaryNameAndPath()
+}
+
 func getRstBinaryNameAndPath() (string, string) {
 	for _, candidate := range rst2Binaries {
 		if pth := hexec.LookPath(candidate); pth != "" {
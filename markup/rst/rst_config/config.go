@@ -0,0 +1,26 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rst_config holds reStructuredText related configuration.
+package rst_config
+
+// Default holds Hugo's default rst configuration.
+var Default = Config{}
+
+// Config configures the reStructuredText external helper.
+type Config struct {
+	// Path is an optional binary name to use instead of the default
+	// rst2html/rst2html.py candidates. It must resolve via $PATH and is
+	// subject to the security.exec.allow policy.
+	Path string
+}
@@ -40,6 +40,35 @@ func (b *Builder) AddAt(h *Heading, row, level int) {
 	b.toc.addAt(h, row, level)
 }
 
+// AddFragments appends the headings of the given ToC after the rows added
+// to the Builder so far. This merges per-fragment ToCs, e.g. from a page
+// assembled from several content files, into one consistent tree: every
+// top level heading of toc starts a new row, with its nested headings
+// preserved below it. The headings are copied, so later changes to toc do
+// not leak into the merged ToC.
+func (b *Builder) AddFragments(toc *Fragments) {
+	if toc == nil {
+		return
+	}
+	row := -1
+	if b.toc != nil {
+		row = len(b.toc.Headings) - 1
+	}
+	for _, h := range toc.Headings {
+		row++
+		b.AddAt(cloneHeading(h), row, 0)
+	}
+}
+
+func cloneHeading(h *Heading) *Heading {
+	c := *h
+	c.Headings = make(Headings, len(h.Headings))
+	for i, hh := range h.Headings {
+		c.Headings[i] = cloneHeading(hh)
+	}
+	return &c
+}
+
 // Build returns the ToC.
 func (b Builder) Build() *Fragments {
 	if b.toc == nil {
@@ -216,3 +216,50 @@ func BenchmarkToc(b *testing.B) {
 		}
 	})
 }
+
+func TestBuilderAddFragments(t *testing.T) {
+	c := qt.New(t)
+
+	var b1 Builder
+	b1.AddAt(&Heading{Title: "Fragment 1", ID: "f1"}, 0, 0)
+	b1.AddAt(&Heading{Title: "1-H2-1", ID: "1-h2-1"}, 0, 1)
+	frag1 := b1.Build()
+
+	var b2 Builder
+	b2.AddAt(&Heading{Title: "Fragment 2", ID: "f2"}, 0, 0)
+	b2.AddAt(&Heading{Title: "2-H2-1", ID: "2-h2-1"}, 0, 1)
+	b2.AddAt(&Heading{Title: "2-H3-1", ID: "2-h3-1"}, 0, 2)
+	frag2 := b2.Build()
+
+	var merged Builder
+	merged.AddFragments(frag1)
+	merged.AddFragments(frag2)
+	toc := merged.Build()
+
+	c.Assert(toc.Headings, qt.HasLen, 2)
+	c.Assert(toc.Identifiers, qt.DeepEquals, collections.SortedStringSlice{"1-h2-1", "2-h2-1", "2-h3-1", "f1", "f2"})
+
+	got := string(toc.ToHTML(1, -1, false))
+	c.Assert(got, qt.Equals, `<nav id="TableOfContents">
+  <ul>
+    <li><a href="#f1">Fragment 1</a>
+      <ul>
+        <li><a href="#1-h2-1">1-H2-1</a></li>
+      </ul>
+    </li>
+    <li><a href="#f2">Fragment 2</a>
+      <ul>
+        <li><a href="#2-h2-1">2-H2-1</a>
+          <ul>
+            <li><a href="#2-h3-1">2-H3-1</a></li>
+          </ul>
+        </li>
+      </ul>
+    </li>
+  </ul>
+</nav>`)
+
+	// The merged tree holds copies, so mutating a fragment does not leak.
+	frag1.Headings[0].Title = "Changed"
+	c.Assert(toc.Headings[0].Title, qt.Equals, "Fragment 1")
+}
@@ -46,10 +46,54 @@ type Provider interface {
 	Reset()
 }
 
+// NewForwardingProvider returns a Provider that reports every measurement
+// to sink while delegating storage and reporting to inner. This lets
+// embedders stream timings to an external collector, e.g. StatsD or
+// Prometheus, without changing any call sites.
+func NewForwardingProvider(inner Provider, sink func(key string, d time.Duration)) Provider {
+	return &forwardingProvider{inner: inner, sink: sink}
+}
+
+type forwardingProvider struct {
+	inner Provider
+	sink  func(key string, d time.Duration)
+}
+
+func (p *forwardingProvider) MeasureSince(key string, start time.Time) {
+	p.sink(key, time.Since(start))
+	p.inner.MeasureSince(key, start)
+}
+
+func (p *forwardingProvider) TrackValue(key string, value any, cached bool) {
+	p.inner.TrackValue(key, value, cached)
+}
+
+func (p *forwardingProvider) WriteMetrics(w io.Writer) {
+	p.inner.WriteMetrics(w)
+}
+
+func (p *forwardingProvider) Reset() {
+	p.inner.Reset()
+}
+
 type diff struct {
 	baseline any
 	count    int
 	simSum   int
+
+	// Shortest and longest tracked output, in bytes. Only maintained when
+	// length tracking is enabled, see NewProviderWithLengthTracking.
+	lenMin int
+	lenMax int
+}
+
+func (d *diff) addLen(n int) {
+	if d.count == 0 || n < d.lenMin {
+		d.lenMin = n
+	}
+	if n > d.lenMax {
+		d.lenMax = n
+	}
 }
 
 func (d *diff) add(v any) *diff {
@@ -68,29 +112,74 @@ func (d *diff) add(v any) *diff {
 
 // Store provides storage for a set of metrics.
 type Store struct {
-	calculateHints bool
-	metrics        map[string][]time.Duration
-	mu             sync.Mutex
-	diffs          map[string]*diff
-	diffmu         sync.Mutex
-	cached         map[string]int
-	cachedmu       sync.Mutex
+	calculateHints  bool
+	trackLengths    bool
+	maxSamples      int
+	maxTotalSamples int
+	totalSamples    int
+	metrics         map[string][]time.Duration
+	mu              sync.Mutex
+	diffs           map[string]*diff
+	diffmu          sync.Mutex
+	cached          map[string]int
+	cachedmu        sync.Mutex
 }
 
 // NewProvider returns a new instance of a metric store.
 func NewProvider(calculateHints bool) Provider {
+	return NewProviderWithMaxSamples(calculateHints, 0)
+}
+
+// NewProviderWithMaxSamples returns a new instance of a metric store that
+// retains only the last maxSamples measurements per key, so stats in
+// WriteMetrics are computed over a rolling window. A maxSamples <= 0 keeps
+// every measurement.
+func NewProviderWithMaxSamples(calculateHints bool, maxSamples int) Provider {
+	return &Store{
+		calculateHints: calculateHints,
+		maxSamples:     maxSamples,
+		metrics:        make(map[string][]time.Duration),
+		diffs:          make(map[string]*diff),
+		cached:         make(map[string]int),
+	}
+}
+
+// NewProviderWithLengthTracking returns a new instance of a metric store
+// that, in addition to the exact-value hints, records the output length of
+// every tracked value. WriteMetrics uses the lengths to flag templates
+// whose outputs are near-identical in length but not in content; those
+// often differ only by a timestamp or similar and may be cacheable after
+// normalization.
+func NewProviderWithLengthTracking(calculateHints bool) Provider {
 	return &Store{
 		calculateHints: calculateHints,
+		trackLengths:   true,
 		metrics:        make(map[string][]time.Duration),
 		diffs:          make(map[string]*diff),
 		cached:         make(map[string]int),
 	}
 }
 
+// NewProviderWithMaxTotalSamples returns a new instance of a metric store
+// that bounds the total number of measurements retained across all keys.
+// When the cap is exceeded, the oldest measurements are evicted from the
+// keys holding the most samples, so memory stays bounded regardless of how
+// many keys are tracked. A maxTotalSamples <= 0 keeps every measurement.
+func NewProviderWithMaxTotalSamples(calculateHints bool, maxTotalSamples int) Provider {
+	return &Store{
+		calculateHints:  calculateHints,
+		maxTotalSamples: maxTotalSamples,
+		metrics:         make(map[string][]time.Duration),
+		diffs:           make(map[string]*diff),
+		cached:          make(map[string]int),
+	}
+}
+
 // Reset clears the metrics store.
 func (s *Store) Reset() {
 	s.mu.Lock()
 	s.metrics = make(map[string][]time.Duration)
+	s.totalSamples = 0
 	s.mu.Unlock()
 
 	s.diffmu.Lock()
@@ -116,6 +205,11 @@ func (s *Store) TrackValue(key string, value any, cached bool) {
 		s.diffs[key] = d
 	}
 
+	if s.trackLengths {
+		if vs, ok := types.TypeToString(value); ok {
+			d.addLen(len(vs))
+		}
+	}
 	d.add(value)
 	s.diffmu.Unlock()
 
@@ -126,16 +220,107 @@ func (s *Store) TrackValue(key string, value any, cached bool) {
 	}
 }
 
+// PartialCachedCandidates returns the names of the tracked values that look
+// like good partialCached candidates: tracked more than once with every
+// value identical (a cache potential of 100). The result is sorted and it
+// is safe to call concurrently with TrackValue. It is empty unless the
+// store was created with calculateHints enabled.
+func (s *Store) PartialCachedCandidates() []string {
+	s.diffmu.Lock()
+	defer s.diffmu.Unlock()
+
+	var candidates []string
+	for k, d := range s.diffs {
+		if d.count > 1 && d.simSum/d.count == 100 {
+			candidates = append(candidates, k)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// PartialNormalizationCandidates returns the names of the tracked values
+// whose outputs are never identical, but always near-identical in length.
+// Length is a cheap proxy for outputs that differ only in a small dynamic
+// part, e.g. a timestamp, so these may be cacheable after normalization.
+// The result is sorted and empty unless the store was created with
+// NewProviderWithLengthTracking and calculateHints enabled.
+func (s *Store) PartialNormalizationCandidates() []string {
+	if !s.trackLengths {
+		return nil
+	}
+
+	s.diffmu.Lock()
+	defer s.diffmu.Unlock()
+
+	var candidates []string
+	for k, d := range s.diffs {
+		if d.count > 1 && d.simSum/d.count < 100 && isNearIdenticalLength(d.lenMin, d.lenMax) {
+			candidates = append(candidates, k)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// isNearIdenticalLength reports whether the given min and max output
+// lengths are within 5% (and at least 2 bytes) of each other.
+func isNearIdenticalLength(lenMin, lenMax int) bool {
+	threshold := lenMax / 20
+	if threshold < 2 {
+		threshold = 2
+	}
+	return lenMax-lenMin <= threshold
+}
+
 // MeasureSince adds a measurement for key to the metric store.
 func (s *Store) MeasureSince(key string, start time.Time) {
 	s.mu.Lock()
-	s.metrics[key] = append(s.metrics[key], time.Since(start))
+	m := append(s.metrics[key], time.Since(start))
+	s.totalSamples++
+	if s.maxSamples > 0 && len(m) > s.maxSamples {
+		s.totalSamples -= len(m) - s.maxSamples
+		m = m[len(m)-s.maxSamples:]
+	}
+	s.metrics[key] = m
+	s.evictOverTotal()
 	s.mu.Unlock()
 }
 
-// WriteMetrics writes a summary of the metrics to w.
-func (s *Store) WriteMetrics(w io.Writer) {
+// evictOverTotal evicts the oldest samples from the largest keys until the
+// total number of retained samples is within maxTotalSamples.
+// Must be called with s.mu held.
+func (s *Store) evictOverTotal() {
+	for s.maxTotalSamples > 0 && s.totalSamples > s.maxTotalSamples {
+		var largest string
+		var max int
+		for k, v := range s.metrics {
+			if len(v) > max {
+				max, largest = len(v), k
+			}
+		}
+		if max == 0 {
+			return
+		}
+		n := s.totalSamples - s.maxTotalSamples
+		if n > max {
+			n = max
+		}
+		if n == max {
+			delete(s.metrics, largest)
+		} else {
+			s.metrics[largest] = s.metrics[largest][n:]
+		}
+		s.totalSamples -= n
+	}
+}
+
+// snapshotResults computes the per-key results under the lock, so the
+// returned slice is a consistent snapshot that can be written without
+// holding up concurrent measurements.
+func (s *Store) snapshotResults() []result {
 	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	results := make([]result, len(s.metrics))
 
@@ -165,8 +350,61 @@ func (s *Store) WriteMetrics(w io.Writer) {
 		i++
 	}
 
-	s.mu.Unlock()
+	return results
+}
+
+// WriteMetrics writes a summary of the metrics to w.
+func (s *Store) WriteMetrics(w io.Writer) {
+	results := s.snapshotResults()
+
+	s.writeMetricsHeader(w)
+
+	sort.Sort(bySum(results))
+	for _, v := range results {
+		s.writeResult(w, v)
+	}
 
+	s.writeMetricsFooter(w)
+}
+
+// WriteMetricsStreaming is like WriteMetrics, but writes the sorted rows one
+// at a time and flushes w after every flushEvery rows, so a live display can
+// show partial results while a long report is being written. A flushEvery
+// <= 0 flushes after every row. The snapshot of the metrics is taken up
+// front, so measurements added while streaming don't show up mid-report.
+// Flushing is a no-op unless w has a Flush method, e.g. a *bufio.Writer.
+func (s *Store) WriteMetricsStreaming(w io.Writer, flushEvery int) {
+	if flushEvery <= 0 {
+		flushEvery = 1
+	}
+
+	flush := func() {}
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		flush = func() { _ = f.Flush() }
+	case interface{ Flush() }:
+		flush = f.Flush
+	}
+
+	results := s.snapshotResults()
+	sort.Sort(bySum(results))
+
+	s.writeMetricsHeader(w)
+	flush()
+
+	for i, v := range results {
+		s.writeResult(w, v)
+		if (i+1)%flushEvery == 0 {
+			flush()
+		}
+	}
+
+	s.writeMetricsFooter(w)
+	flush()
+}
+
+// writeMetricsHeader writes the table header for the metrics report.
+func (s *Store) writeMetricsHeader(w io.Writer) {
 	if s.calculateHints {
 		fmt.Fprintf(w, "  %13s  %12s  %12s  %9s  %7s  %6s  %5s  %s\n", "cumulative", "average", "maximum", "cache", "percent", "cached", "total", "")
 		fmt.Fprintf(w, "  %13s  %12s  %12s  %9s  %7s  %6s  %5s  %s\n", "duration", "duration", "duration", "potential", "cached", "count", "count", "template")
@@ -175,15 +413,26 @@ func (s *Store) WriteMetrics(w io.Writer) {
 		fmt.Fprintf(w, "  %13s  %12s  %12s  %5s  %s\n", "cumulative", "average", "maximum", "", "")
 		fmt.Fprintf(w, "  %13s  %12s  %12s  %5s  %s\n", "duration", "duration", "duration", "count", "template")
 		fmt.Fprintf(w, "  %13s  %12s  %12s  %5s  %s\n", "----------", "--------", "--------", "-----", "--------")
+	}
+}
 
+// writeResult writes one row of the metrics report.
+func (s *Store) writeResult(w io.Writer, v result) {
+	if s.calculateHints {
+		fmt.Fprintf(w, "  %13s  %12s  %12s  %9d  %7.f  %6d  %5d  %s\n", v.sum, v.avg, v.max, v.cacheFactor, float64(v.cacheCount)/float64(v.count)*100, v.cacheCount, v.count, v.key)
+	} else {
+		fmt.Fprintf(w, "  %13s  %12s  %12s  %5d  %s\n", v.sum, v.avg, v.max, v.count, v.key)
 	}
+}
 
-	sort.Sort(bySum(results))
-	for _, v := range results {
-		if s.calculateHints {
-			fmt.Fprintf(w, "  %13s  %12s  %12s  %9d  %7.f  %6d  %5d  %s\n", v.sum, v.avg, v.max, v.cacheFactor, float64(v.cacheCount)/float64(v.count)*100, v.cacheCount, v.count, v.key)
-		} else {
-			fmt.Fprintf(w, "  %13s  %12s  %12s  %5d  %s\n", v.sum, v.avg, v.max, v.count, v.key)
+// writeMetricsFooter writes the normalization candidates footnote, if any.
+func (s *Store) writeMetricsFooter(w io.Writer) {
+	if s.trackLengths && s.calculateHints {
+		if candidates := s.PartialNormalizationCandidates(); len(candidates) > 0 {
+			fmt.Fprint(w, "\n  The below have outputs of near-identical length and are possibly cacheable with normalization:\n")
+			for _, k := range candidates {
+				fmt.Fprintf(w, "  %s\n", k)
+			}
 		}
 	}
 }
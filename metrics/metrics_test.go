@@ -17,6 +17,7 @@ import (
 	"html/template"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gohugoio/hugo/resources/page"
 
@@ -67,3 +68,167 @@ func BenchmarkHowSimilar(b *testing.B) {
 		howSimilar(s1, s2)
 	}
 }
+
+func TestMaxSamples(t *testing.T) {
+	c := qt.New(t)
+
+	s := NewProviderWithMaxSamples(false, 3).(*Store)
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		s.MeasureSince("foo", start)
+	}
+	s.MeasureSince("bar", start)
+
+	c.Assert(len(s.metrics["foo"]), qt.Equals, 3)
+	c.Assert(len(s.metrics["bar"]), qt.Equals, 1)
+
+	// Unbounded by default.
+	s = NewProvider(false).(*Store)
+	for i := 0; i < 10; i++ {
+		s.MeasureSince("foo", start)
+	}
+	c.Assert(len(s.metrics["foo"]), qt.Equals, 10)
+}
+
+func TestMaxTotalSamples(t *testing.T) {
+	c := qt.New(t)
+
+	s := NewProviderWithMaxTotalSamples(false, 10).(*Store)
+	start := time.Now()
+	for i := 0; i < 8; i++ {
+		s.MeasureSince("foo", start)
+	}
+	for i := 0; i < 8; i++ {
+		s.MeasureSince("bar", start)
+	}
+
+	c.Assert(s.totalSamples, qt.Equals, 10)
+	total := 0
+	for _, v := range s.metrics {
+		total += len(v)
+	}
+	c.Assert(total, qt.Equals, 10)
+	// The largest keys pay for the eviction, so the sizes converge.
+	c.Assert(len(s.metrics["foo"]) < 8, qt.IsTrue)
+	c.Assert(len(s.metrics["bar"]) < 8, qt.IsTrue)
+
+	s.Reset()
+	c.Assert(s.totalSamples, qt.Equals, 0)
+}
+
+func TestPartialCachedCandidates(t *testing.T) {
+	c := qt.New(t)
+
+	s := NewProvider(true).(*Store)
+
+	for i := 0; i < 3; i++ {
+		s.TrackValue("static", "same value", false)
+	}
+	s.TrackValue("dynamic", "value 1", false)
+	s.TrackValue("dynamic", "something completely different", false)
+	s.TrackValue("once", "only once", false)
+
+	c.Assert(s.PartialCachedCandidates(), qt.DeepEquals, []string{"static"})
+
+	// No hints tracked when disabled.
+	s = NewProvider(false).(*Store)
+	s.TrackValue("static", "same value", false)
+	s.TrackValue("static", "same value", false)
+	c.Assert(s.PartialCachedCandidates(), qt.HasLen, 0)
+}
+
+func TestPartialNormalizationCandidates(t *testing.T) {
+	c := qt.New(t)
+
+	s := NewProviderWithLengthTracking(true).(*Store)
+
+	// Same length, different content, e.g. a timestamp.
+	s.TrackValue("timestamped", "updated 2024-01-02", false)
+	s.TrackValue("timestamped", "updated 2024-03-04", false)
+	// Identical values are already covered by PartialCachedCandidates.
+	s.TrackValue("static", "same value", false)
+	s.TrackValue("static", "same value", false)
+	// Genuinely different outputs.
+	s.TrackValue("dynamic", "short", false)
+	s.TrackValue("dynamic", "something completely different and much longer", false)
+	s.TrackValue("once", "only once", false)
+
+	c.Assert(s.PartialNormalizationCandidates(), qt.DeepEquals, []string{"timestamped"})
+
+	var sb strings.Builder
+	s.WriteMetrics(&sb)
+	c.Assert(sb.String(), qt.Contains, "possibly cacheable with normalization")
+	c.Assert(sb.String(), qt.Contains, "timestamped")
+
+	// Opt-in: the default provider does not track lengths.
+	s = NewProvider(true).(*Store)
+	s.TrackValue("timestamped", "updated 2024-01-02", false)
+	s.TrackValue("timestamped", "updated 2024-03-04", false)
+	c.Assert(s.PartialNormalizationCandidates(), qt.HasLen, 0)
+}
+
+func TestForwardingProvider(t *testing.T) {
+	c := qt.New(t)
+
+	var keys []string
+	inner := NewProvider(false)
+	p := NewForwardingProvider(inner, func(key string, d time.Duration) {
+		keys = append(keys, key)
+		c.Assert(d >= 0, qt.IsTrue)
+	})
+
+	start := time.Now()
+	p.MeasureSince("foo", start)
+	p.MeasureSince("bar", start)
+
+	c.Assert(keys, qt.DeepEquals, []string{"foo", "bar"})
+	// The measurements are also stored in the inner provider.
+	c.Assert(len(inner.(*Store).metrics["foo"]), qt.Equals, 1)
+	c.Assert(len(inner.(*Store).metrics["bar"]), qt.Equals, 1)
+
+	p.Reset()
+	c.Assert(len(inner.(*Store).metrics), qt.Equals, 0)
+}
+
+type flushRecorder struct {
+	strings.Builder
+	flushes int
+}
+
+func (f *flushRecorder) Flush() { f.flushes++ }
+
+func TestWriteMetricsStreaming(t *testing.T) {
+	c := qt.New(t)
+
+	s := NewProvider(false).(*Store)
+	start := time.Now()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		s.MeasureSince(key, start)
+	}
+
+	var w flushRecorder
+	s.WriteMetricsStreaming(&w, 2)
+
+	out := w.String()
+	c.Assert(out, qt.Contains, "template")
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		c.Assert(out, qt.Contains, "  "+key+"\n")
+	}
+	// One flush after the header, one per two rows and one at the end.
+	c.Assert(w.flushes, qt.Equals, 4)
+
+	// Same rows as the one-shot report.
+	var sb strings.Builder
+	s.WriteMetrics(&sb)
+	c.Assert(out, qt.Equals, sb.String())
+
+	// A flushEvery <= 0 flushes after every row.
+	w = flushRecorder{}
+	s.WriteMetricsStreaming(&w, 0)
+	c.Assert(w.flushes, qt.Equals, 7)
+
+	// A writer without a Flush method is fine.
+	var plain strings.Builder
+	s.WriteMetricsStreaming(&plain, 2)
+	c.Assert(plain.String(), qt.Equals, out)
+}
@@ -14,6 +14,8 @@
 package minifiers
 
 import (
+	"fmt"
+
 	"github.com/gohugoio/hugo/common/maps"
 	"github.com/spf13/cast"
 
@@ -64,6 +66,11 @@ type MinifyConfig struct {
 	// Whether to minify the published output (the HTML written to /public).
 	MinifyOutput bool
 
+	// InlineThreshold is a size in bytes below which minified CSS/JS assets
+	// are considered candidates for inlining into the HTML. 0 (default)
+	// disables the threshold check.
+	InlineThreshold int
+
 	DisableHTML bool
 	DisableCSS  bool
 	DisableJS   bool
@@ -71,10 +78,41 @@ type MinifyConfig struct {
 	DisableSVG  bool
 	DisableXML  bool
 
+	// SourceMap configures source map emission for minified JS and CSS
+	// assets. Default off.
+	SourceMap SourceMapConfig
+
+	// KeepBangComments configures whether minified CSS and JS keep comments
+	// starting with /*!, commonly used for legally required license
+	// banners. Default on for both, matching what most minification tools
+	// do.
+	KeepBangComments KeepBangCommentsConfig
+
 	Tdewolff TdewolffConfig
 }
 
+// KeepBangCommentsConfig holds the per-type bang comment switches. The
+// underlying minifiers strip all comments, so the banners are extracted
+// before minification and re-emitted at the top of the output.
+type KeepBangCommentsConfig struct {
+	JS  bool
+	CSS bool
+}
+
+// SourceMapConfig holds the per-type source map switches. Note that the
+// underlying minifiers don't track input positions, so the emitted maps
+// carry the original source in sourcesContent without fine-grained segment
+// mappings; browsers will show the original file next to the minified one.
+type SourceMapConfig struct {
+	JS  bool
+	CSS bool
+}
+
 var defaultConfig = MinifyConfig{
+	KeepBangComments: KeepBangCommentsConfig{
+		JS:  true,
+		CSS: true,
+	},
 	Tdewolff: defaultTdewolffConfig,
 }
 
@@ -131,5 +169,22 @@ func DecodeConfig(v any) (conf MinifyConfig, err error) {
 		return
 	}
 
+	// The precision is the number of significant digits to keep; 0 means
+	// full precision. The underlying minifier applies it to all numbers, so
+	// a negative value is always a misconfiguration.
+	if conf.Tdewolff.CSS.Precision < 0 {
+		err = fmt.Errorf("minify: tdewolff.css.precision must be >= 0, got %d", conf.Tdewolff.CSS.Precision)
+		return
+	}
+	if conf.Tdewolff.SVG.Precision < 0 {
+		err = fmt.Errorf("minify: tdewolff.svg.precision must be >= 0, got %d", conf.Tdewolff.SVG.Precision)
+		return
+	}
+
+	if conf.InlineThreshold < 0 {
+		err = fmt.Errorf("minify: inlineThreshold must be >= 0, got %d", conf.InlineThreshold)
+		return
+	}
+
 	return
 }
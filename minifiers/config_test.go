@@ -19,6 +19,7 @@ import (
 	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/config/testconfig"
+	"github.com/gohugoio/hugo/minifiers"
 )
 
 func TestConfig(t *testing.T) {
@@ -101,3 +102,26 @@ func TestConfigNewCommentOptions(t *testing.T) {
 	c.Assert(conf.Tdewolff.HTML.KeepSpecialComments, qt.Equals, true)
 	c.Assert(conf.Tdewolff.SVG.Precision, qt.Equals, 10)
 }
+
+func TestConfigPrecision(t *testing.T) {
+	c := qt.New(t)
+
+	// Defaults, full precision.
+	conf, err := minifiers.DecodeConfig(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Tdewolff.CSS.Precision, qt.Equals, 0)
+	c.Assert(conf.Tdewolff.SVG.Precision, qt.Equals, 0)
+
+	v := config.New()
+	v.Set("minify", map[string]any{
+		"tdewolff": map[string]any{
+			"css": map[string]any{
+				"precision": -2,
+			},
+		},
+	})
+
+	_, err = minifiers.DecodeConfig(v.Get("minify"))
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(err.Error(), qt.Contains, "precision must be >= 0")
+}
@@ -17,7 +17,10 @@
 package minifiers
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
+	"path"
 	"regexp"
 
 	"github.com/gohugoio/hugo/config"
@@ -33,9 +36,23 @@ type Client struct {
 	// Whether output minification is enabled (HTML in /public)
 	MinifyOutput bool
 
+	// Size in bytes below which minified assets are candidates for
+	// inlining. 0 disables.
+	inlineThreshold int
+
+	// Per-type source map switches, as configured with minify.sourceMap.
+	sourceMap SourceMapConfig
+
 	m *minify.M
 }
 
+// ShouldInline reports whether the given minified output is small enough to
+// be a candidate for inlining into the HTML, as configured with
+// minify.inlineThreshold.
+func (m Client) ShouldInline(b []byte) bool {
+	return m.inlineThreshold > 0 && len(b) < m.inlineThreshold
+}
+
 // Transformer returns a func that can be used in the transformer publishing chain.
 // TODO(bep) minify config etc
 func (m Client) Transformer(mediatype media.Type) transform.Transformer {
@@ -57,6 +74,49 @@ func (m Client) Minify(mediatype media.Type, dst io.Writer, src io.Reader) error
 	return m.m.Minify(mediatype.Type, dst, src)
 }
 
+// MinifyWithSourceMap is like Minify, but also returns a version 3 source
+// map when enabled for the given MIME type with minify.sourceMap; the map
+// is nil otherwise. The minified output gets a sourceMappingURL comment
+// pointing to sourceName + ".map", and the map embeds the original source
+// via sourcesContent. See SourceMapConfig for the fidelity caveat.
+func (m Client) MinifyWithSourceMap(mediatype media.Type, src []byte, sourceName string) (minified, sourcemap []byte, err error) {
+	var buf bytes.Buffer
+	if err := m.m.Minify(mediatype.Type, &buf, bytes.NewReader(src)); err != nil {
+		return nil, nil, err
+	}
+	minified = buf.Bytes()
+
+	var comment string
+	mapURL := path.Base(sourceName) + ".map"
+	switch {
+	case mediatype.SubType == "css" && m.sourceMap.CSS:
+		comment = "\n/*# sourceMappingURL=" + mapURL + " */"
+	case isJSSubType(mediatype.SubType) && m.sourceMap.JS:
+		comment = "\n//# sourceMappingURL=" + mapURL
+	default:
+		return minified, nil, nil
+	}
+
+	sourcemap, err = json.Marshal(map[string]any{
+		"version":        3,
+		"file":           path.Base(sourceName),
+		"sources":        []string{path.Base(sourceName)},
+		"sourcesContent": []string{string(src)},
+		"mappings":       "AAAA",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return append(minified, comment...), sourcemap, nil
+}
+
+var jsSubTypeRe = regexp.MustCompile(`^(x-)?(java|ecma)script$`)
+
+func isJSSubType(subType string) bool {
+	return subType == "javascript" || jsSubTypeRe.MatchString(subType)
+}
+
 // noopMinifier implements minify.Minifier [1], but doesn't minify content. This means
 // that we can avoid missing minifiers for any MIME types in our minify.M, which
 // causes minify to return errors, while still allowing minification to be
@@ -71,6 +131,37 @@ func (m noopMinifier) Minify(_ *minify.M, w io.Writer, r io.Reader, _ map[string
 	return err
 }
 
+// bangCommentMinifier wraps a CSS or JS minifier and takes over the
+// handling of comments starting with /*!, e.g. legally required license
+// banners. With keep set, the banners are emitted verbatim before the
+// minified output, each on its own line; otherwise they are dropped. They
+// are always removed from the source handed to the wrapped minifier, whose
+// own comment handling differs between CSS and JS. Note that the banners
+// are found with a simple scan of the source, so a /*! ... */ sequence
+// inside a string literal would also be treated as one.
+type bangCommentMinifier struct {
+	inner minify.Minifier
+	keep  bool
+}
+
+var bangCommentRe = regexp.MustCompile(`(?s)/\*!.*?\*/`)
+
+func (b bangCommentMinifier) Minify(m *minify.M, w io.Writer, r io.Reader, params map[string]string) error {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if b.keep {
+		for _, banner := range bangCommentRe.FindAll(src, -1) {
+			if _, err := w.Write(append(banner, '\n')); err != nil {
+				return err
+			}
+		}
+	}
+	src = bangCommentRe.ReplaceAll(src, nil)
+	return b.inner.Minify(m, w, bytes.NewReader(src), params)
+}
+
 // New creates a new Client with the provided MIME types as the mapping foundation.
 // The HTML minifier is also registered for additional HTML types (AMP etc.) in the
 // provided list of output formats.
@@ -78,11 +169,14 @@ func New(mediaTypes media.Types, outputFormats output.Formats, cfg config.AllPro
 	conf := cfg.GetConfigSection("minify").(MinifyConfig)
 	m := minify.New()
 
+	cssMin := bangCommentMinifier{inner: getMinifier(conf, "css"), keep: conf.KeepBangComments.CSS}
+	jsMin := bangCommentMinifier{inner: getMinifier(conf, "js"), keep: conf.KeepBangComments.JS}
+
 	// We use the Type definition of the media types defined in the site if found.
-	addMinifier(m, mediaTypes, "css", getMinifier(conf, "css"))
+	addMinifier(m, mediaTypes, "css", cssMin)
 
-	addMinifier(m, mediaTypes, "js", getMinifier(conf, "js"))
-	m.AddRegexp(regexp.MustCompile("^(application|text)/(x-)?(java|ecma)script$"), getMinifier(conf, "js"))
+	addMinifier(m, mediaTypes, "js", jsMin)
+	m.AddRegexp(regexp.MustCompile("^(application|text)/(x-)?(java|ecma)script$"), jsMin)
 
 	addMinifier(m, mediaTypes, "json", getMinifier(conf, "json"))
 	m.AddRegexp(regexp.MustCompile(`^(application|text)/(x-|(ld|manifest)\+)?json$`), getMinifier(conf, "json"))
@@ -99,7 +193,7 @@ func New(mediaTypes media.Types, outputFormats output.Formats, cfg config.AllPro
 		}
 	}
 
-	return Client{m: m, MinifyOutput: conf.MinifyOutput}, nil
+	return Client{m: m, MinifyOutput: conf.MinifyOutput, inlineThreshold: conf.InlineThreshold, sourceMap: conf.SourceMap}, nil
 }
 
 // getMinifier returns the appropriate minify.MinifierFunc for the MIME
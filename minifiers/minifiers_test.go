@@ -212,3 +212,92 @@ func TestDecodeConfigKeepWhitespace(t *testing.T) {
 		},
 	)
 }
+
+func TestShouldInline(t *testing.T) {
+	c := qt.New(t)
+	v := config.New()
+	v.Set("minify", map[string]any{
+		"inlinethreshold": 10,
+	})
+	m, err := minifiers.New(media.DefaultTypes, output.DefaultFormats, testconfig.GetTestConfig(nil, v))
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.ShouldInline([]byte("tiny")), qt.Equals, true)
+	c.Assert(m.ShouldInline([]byte("this is well over ten bytes")), qt.Equals, false)
+
+	// Default is disabled.
+	m, err = minifiers.New(media.DefaultTypes, output.DefaultFormats, testconfig.GetTestConfig(nil, nil))
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.ShouldInline([]byte("tiny")), qt.Equals, false)
+}
+
+func TestMinifyWithSourceMap(t *testing.T) {
+	c := qt.New(t)
+	v := config.New()
+	v.Set("minify", map[string]any{
+		"sourcemap": map[string]any{
+			"js":  true,
+			"css": true,
+		},
+	})
+	m, err := minifiers.New(media.DefaultTypes, output.DefaultFormats, testconfig.GetTestConfig(afero.NewMemMapFs(), v))
+	c.Assert(err, qt.IsNil)
+
+	src := " var  foo =1 ;   foo ++  ;  "
+	minified, sourcemap, err := m.MinifyWithSourceMap(media.Builtin.JavascriptType, []byte(src), "js/main.js")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(minified), qt.Equals, "var foo=1;foo++\n//# sourceMappingURL=main.js.map")
+	var sm map[string]any
+	c.Assert(json.Unmarshal(sourcemap, &sm), qt.IsNil)
+	c.Assert(sm["version"], qt.Equals, float64(3))
+	c.Assert(sm["sourcesContent"], qt.DeepEquals, []any{src})
+
+	minified, sourcemap, err = m.MinifyWithSourceMap(media.Builtin.CSSType, []byte(" body { color: blue; }  "), "main.css")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(minified), qt.Equals, "body{color:blue}\n/*# sourceMappingURL=main.css.map */")
+	c.Assert(sourcemap, qt.IsNotNil)
+
+	// Off by default.
+	m, err = minifiers.New(media.DefaultTypes, output.DefaultFormats, testconfig.GetTestConfig(afero.NewMemMapFs(), nil))
+	c.Assert(err, qt.IsNil)
+	minified, sourcemap, err = m.MinifyWithSourceMap(media.Builtin.CSSType, []byte(" body { color: blue; }  "), "main.css")
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(minified), qt.Equals, "body{color:blue}")
+	c.Assert(sourcemap, qt.IsNil)
+}
+
+func TestKeepBangComments(t *testing.T) {
+	c := qt.New(t)
+
+	// On by default.
+	m, _ := minifiers.New(media.DefaultTypes, output.DefaultFormats, testconfig.GetTestConfig(afero.NewMemMapFs(), nil))
+
+	var b bytes.Buffer
+	c.Assert(m.Minify(media.Builtin.CSSType, &b, strings.NewReader("/*! My License */\nbody { color: blue; }")), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "/*! My License */\nbody{color:blue}")
+
+	b.Reset()
+	c.Assert(m.Minify(media.Builtin.JavascriptType, &b, strings.NewReader("/*! My License */\n var  foo =1 ;")), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "/*! My License */\nvar foo=1")
+
+	// Regular comments are still stripped.
+	b.Reset()
+	c.Assert(m.Minify(media.Builtin.CSSType, &b, strings.NewReader("/* plain */\nbody { color: blue; }")), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "body{color:blue}")
+
+	// Can be turned off.
+	v := config.New()
+	v.Set("minify", map[string]any{
+		"keepbangcomments": map[string]any{
+			"css": false,
+			"js":  false,
+		},
+	})
+	conf := testconfig.GetTestConfigs(nil, v).Base.Minify
+	c.Assert(conf.KeepBangComments.CSS, qt.Equals, false)
+	c.Assert(conf.KeepBangComments.JS, qt.Equals, false)
+
+	m, _ = minifiers.New(media.DefaultTypes, output.DefaultFormats, testconfig.GetTestConfig(afero.NewMemMapFs(), v))
+	b.Reset()
+	c.Assert(m.Minify(media.Builtin.CSSType, &b, strings.NewReader("/*! My License */\nbody { color: blue; }")), qt.IsNil)
+	c.Assert(b.String(), qt.Equals, "body{color:blue}")
+}
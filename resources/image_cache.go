@@ -14,8 +14,14 @@
 package resources
 
 import (
+	"encoding/json"
 	"image"
 	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
 
 	"github.com/gohugoio/hugo/common/hugio"
 	"github.com/gohugoio/hugo/resources/images"
@@ -29,8 +35,220 @@ import (
 type ImageCache struct {
 	pathSpec *helpers.PathSpec
 
+	// namespace, if set, is prefixed to all keys so variants created
+	// through this cache can't collide with other namespaces.
+	namespace string
+
+	// shardLongFileKeys, if set, hashes overly long file cache keys into
+	// a sharded ab/cd/<hash> directory layout. See fileKey.
+	shardLongFileKeys bool
+
+	// bypass, if set, skips both the memory and the file cache lookups in
+	// getOrCreate, so every lookup recreates the variant. See WithBypass.
+	bypass bool
+
+	// writeSidecars, if set, writes a metadata sidecar next to every image
+	// variant created. See WithMetadataSidecars.
+	writeSidecars bool
+
 	fcache *filecache.Cache
 	mcache *dynacache.Partition[string, *resourceAdapter]
+
+	// stats is shared between all copies of this cache, see WithNamespace.
+	stats *cacheStats
+}
+
+// CacheStats holds lookup counters for the image cache.
+type CacheStats struct {
+	// Hits is the number of lookups served from the memory cache.
+	Hits int
+
+	// Misses is the number of lookups that had to create the image
+	// variant, or read it from the file cache.
+	Misses int
+}
+
+// cacheStats guards CacheStats for concurrent use.
+type cacheStats struct {
+	mu sync.Mutex
+	CacheStats
+}
+
+// ResetStats resets the lookup counters to zero. Together with
+// SnapshotStats this lets watch-mode rebuilds report per-build cache
+// effectiveness instead of accumulating over the whole session.
+func (c *ImageCache) ResetStats() {
+	c.stats.mu.Lock()
+	c.stats.CacheStats = CacheStats{}
+	c.stats.mu.Unlock()
+}
+
+// SnapshotStats returns a copy of the current lookup counters. It is safe
+// to call concurrently with lookups; the copy is taken atomically.
+func (c *ImageCache) SnapshotStats() CacheStats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	return c.stats.CacheStats
+}
+
+// WithNamespace returns a shallow copy of c whose keys are prefixed with the
+// given namespace, e.g. a language code. The backing caches are shared.
+func (c *ImageCache) WithNamespace(namespace string) *ImageCache {
+	cc := *c
+	cc.namespace = namespace
+	return &cc
+}
+
+// WithShardedFileLayout returns a shallow copy of c that hashes file cache
+// keys longer than maxImageFileKeyLength into a sharded ab/cd/<hash>
+// layout, avoiding very long filenames and too many files in one
+// directory. Shorter keys are stored as before, so existing caches remain
+// valid. The memory cache keys, and with them DeleteByPrefix, are not
+// affected by the file layout.
+func (c *ImageCache) WithShardedFileLayout() *ImageCache {
+	cc := *c
+	cc.shardLongFileKeys = true
+	return &cc
+}
+
+// WithBypass returns a shallow copy of c that skips both the memory and the
+// file cache lookups, so every lookup recreates the image variant and
+// overwrites the cached file. Meant for development/server mode where even a
+// byte-identical edit should be re-processed immediately. The caches are
+// still written to, so a later caching run finds fresh entries.
+func (c *ImageCache) WithBypass() *ImageCache {
+	cc := *c
+	cc.bypass = true
+	return &cc
+}
+
+// WithMetadataSidecars returns a shallow copy of c that writes a small
+// <key>.json sidecar next to every image variant it creates, recording the
+// source path, the transformation and the resulting dimensions. This lets
+// cache inspection and cache busting tooling reason about the entries
+// without decoding the images. Off by default; see Metadata for reading the
+// sidecars back.
+func (c *ImageCache) WithMetadataSidecars() *ImageCache {
+	cc := *c
+	cc.writeSidecars = true
+	return &cc
+}
+
+// ImageCacheMetadata is the content of the metadata sidecar written next to
+// a cached image variant, see WithMetadataSidecars.
+type ImageCacheMetadata struct {
+	// SourcePath is the target path of the image the variant was derived
+	// from.
+	SourcePath string `json:"sourcePath"`
+
+	// Action is the image operation, e.g. "resize".
+	Action string `json:"action"`
+
+	// Spec is the full transformation spec as used in the variant's
+	// filename.
+	Spec string `json:"spec"`
+
+	// Width and Height are the dimensions of the derived image.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Metadata returns the metadata sidecar for the image variant with the
+// given target path. The second return value reports whether a sidecar was
+// found; a missing sidecar, e.g. for an entry created before sidecars were
+// enabled, is not an error.
+func (c *ImageCache) Metadata(relTargetPath string) (ImageCacheMetadata, bool, error) {
+	var m ImageCacheMetadata
+	_, b, err := c.fcache.GetBytes(c.fileKey(relTargetPath) + ".json")
+	if err != nil || b == nil {
+		return m, false, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, false, err
+	}
+	return m, true, nil
+}
+
+// writeSidecar writes m next to the cached image file with the given name.
+// The write is atomic: the sidecar is written to a temp file and renamed
+// into place, so a reader never sees a partial sidecar.
+func (c *ImageCache) writeSidecar(name string, m ImageCacheMetadata) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	tmp := name + ".json.tmp"
+	if err := afero.WriteFile(c.fcache.Fs, tmp, b, 0o666); err != nil {
+		return err
+	}
+	return c.fcache.Fs.Rename(tmp, name+".json")
+}
+
+// maxImageFileKeyLength is the longest file cache key stored as-is when
+// the sharded file layout is enabled. Do not change once set in stone;
+// it would invalidate existing caches.
+const maxImageFileKeyLength = 150
+
+// fileKey returns the file cache key for the given target path. With the
+// sharded layout enabled, long keys are replaced with a stable hash split
+// into two levels of subdirectories, keeping the original extension.
+func (c *ImageCache) fileKey(relTargetPath string) string {
+	if !c.shardLongFileKeys || len(relTargetPath) <= maxImageFileKeyLength {
+		return relTargetPath
+	}
+	hash := helpers.MD5String(relTargetPath)
+	return hash[:2] + "/" + hash[2:4] + "/" + hash[4:] + path.Ext(relTargetPath)
+}
+
+// memKey returns the memory cache key for the given target path, applying
+// the language prefix for multihost sites and the cache's namespace.
+// For multihost sites, we duplicate language versions of the same resource,
+// so we need to include the language in the key.
+// Note that we don't need to include the language in the file cache key,
+// as the hash will take care of any different content.
+func (c *ImageCache) memKey(relTargetPath string) string {
+	key := relTargetPath
+	if c.pathSpec.Cfg.IsMultihost() {
+		key = c.pathSpec.Lang() + key
+	}
+	if c.namespace != "" {
+		key = c.namespace + "/" + key
+	}
+	return dynacache.CleanKey(key)
+}
+
+// DeleteByPrefix deletes all image variants in c's namespace whose key
+// starts with prefix from the memory cache and returns the number deleted.
+// An empty prefix clears the whole namespace.
+func (c *ImageCache) DeleteByPrefix(prefix string) int {
+	prefix = c.memKey(prefix)
+
+	var n int
+	for _, k := range c.mcache.Keys() {
+		if strings.HasPrefix(k, prefix) {
+			if c.mcache.Delete(k) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Evict evicts the image variant with the given target path from the memory
+// cache, optionally also removing the backing file from the file cache. Use
+// this when the exact key is known, e.g. from a change event in watch mode;
+// it avoids any prefix scan. It returns whether an entry was found in the
+// memory cache.
+func (c *ImageCache) Evict(relTargetPath string, deleteFromDisk bool) (bool, error) {
+	found := c.mcache.Delete(c.memKey(relTargetPath))
+
+	if deleteFromDisk {
+		if err := c.fcache.Delete(c.fileKey(relTargetPath)); err != nil {
+			return found, err
+		}
+	}
+
+	return found, nil
 }
 
 func (c *ImageCache) getOrCreate(
@@ -39,18 +257,16 @@ func (c *ImageCache) getOrCreate(
 ) (*resourceAdapter, error) {
 	relTarget := parent.relTargetPathFromConfig(conf)
 	relTargetPath := relTarget.TargetPath()
-	memKey := relTargetPath
+	memKey := c.memKey(relTargetPath)
 
-	// For multihost sites, we duplicate language versions of the same resource,
-	// so we need to include the language in the key.
-	// Note that we don't need to include the language in the file cache key,
-	// as the hash will take care of any different content.
-	if c.pathSpec.Cfg.IsMultihost() {
-		memKey = c.pathSpec.Lang() + memKey
+	if c.bypass {
+		// Forget any cached variant so the create func below runs.
+		c.mcache.Delete(memKey)
 	}
-	memKey = dynacache.CleanKey(memKey)
 
+	var created bool
 	v, err := c.mcache.GetOrCreate(memKey, func(key string) (*resourceAdapter, error) {
+		created = true
 		var img *imageResource
 
 		// These funcs are protected by a named lock.
@@ -89,7 +305,20 @@ func (c *ImageCache) getOrCreate(
 			img.setOpenSource(func() (hugio.ReadSeekCloser, error) {
 				return c.fcache.Fs.Open(info.Name)
 			})
-			return img.EncodeTo(conf, conv, w)
+			if err := img.EncodeTo(conf, conv, w); err != nil {
+				return err
+			}
+			if c.writeSidecars {
+				b := conv.Bounds()
+				return c.writeSidecar(info.Name, ImageCacheMetadata{
+					SourcePath: parent.TargetPath(),
+					Action:     conf.Action,
+					Spec:       conf.GetKey(conf.TargetFormat),
+					Width:      b.Dx(),
+					Height:     b.Dy(),
+				})
+			}
+			return nil
 		}
 
 		// Now look in the file cache.
@@ -99,7 +328,18 @@ func (c *ImageCache) getOrCreate(
 		//  but the count of processed image variations for this site.
 		c.pathSpec.ProcessingStats.Incr(&c.pathSpec.ProcessingStats.ProcessedImages)
 
-		_, err := c.fcache.ReadOrCreate(relTargetPath, read, create)
+		var err error
+		if c.bypass {
+			// Skip the disk lookup and overwrite any cached file.
+			var info filecache.ItemInfo
+			var w io.WriteCloser
+			info, w, err = c.fcache.WriteCloser(c.fileKey(relTargetPath))
+			if err == nil {
+				err = create(info, w)
+			}
+		} else {
+			_, err = c.fcache.ReadOrCreate(c.fileKey(relTargetPath), read, create)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -109,6 +349,14 @@ func (c *ImageCache) getOrCreate(
 		return imgAdapter, nil
 	})
 
+	c.stats.mu.Lock()
+	if created {
+		c.stats.Misses++
+	} else {
+		c.stats.Hits++
+	}
+	c.stats.mu.Unlock()
+
 	return v, err
 }
 
@@ -121,5 +369,6 @@ func newImageCache(fileCache *filecache.Cache, memCache *dynacache.Cache, ps *he
 			dynacache.OptionsPartition{ClearWhen: dynacache.ClearOnChange, Weight: 70},
 		),
 		pathSpec: ps,
+		stats:    &cacheStats{},
 	}
 }
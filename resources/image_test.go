@@ -867,3 +867,58 @@ func assertWidthHeight(c *qt.C, img images.ImageResource, w, h int) {
 	c.Assert(img.Width(), qt.Equals, w)
 	c.Assert(img.Height(), qt.Equals, h)
 }
+
+func TestImageCacheBypass(t *testing.T) {
+	c := qt.New(t)
+
+	spec, image := fetchSunset(c)
+
+	// With caching, the second identical transformation is a hit.
+	spec.ImageCache.ResetStats()
+	_, err := image.Resize("301x201")
+	c.Assert(err, qt.IsNil)
+	_, err = image.Resize("301x201")
+	c.Assert(err, qt.IsNil)
+	stats := spec.ImageCache.SnapshotStats()
+	c.Assert(stats.Misses, qt.Equals, 1)
+	c.Assert(stats.Hits, qt.Equals, 1)
+
+	// With bypass, every lookup recreates the variant.
+	spec.ImageCache = spec.ImageCache.WithBypass()
+	spec.ImageCache.ResetStats()
+	for i := 0; i < 3; i++ {
+		resized, err := image.Resize("302x202")
+		c.Assert(err, qt.IsNil)
+		assertWidthHeight(c, resized, 302, 202)
+	}
+	stats = spec.ImageCache.SnapshotStats()
+	c.Assert(stats.Misses, qt.Equals, 3)
+	c.Assert(stats.Hits, qt.Equals, 0)
+}
+
+func TestImageCacheMetadataSidecar(t *testing.T) {
+	c := qt.New(t)
+
+	spec, image := fetchSunset(c)
+
+	// Off by default: no sidecar, and reading one back is not an error.
+	resized, err := image.Resize("304x204")
+	c.Assert(err, qt.IsNil)
+	_, found, err := spec.ImageCache.Metadata(resized.RelPermalink())
+	c.Assert(err, qt.IsNil)
+	c.Assert(found, qt.IsFalse)
+
+	spec.ImageCache = spec.ImageCache.WithMetadataSidecars()
+
+	resized, err = image.Resize("305x205")
+	c.Assert(err, qt.IsNil)
+
+	m, found, err := spec.ImageCache.Metadata(resized.RelPermalink())
+	c.Assert(err, qt.IsNil)
+	c.Assert(found, qt.IsTrue)
+	c.Assert(m.SourcePath, qt.Equals, "/a/sunset.jpg")
+	c.Assert(m.Action, qt.Equals, "resize")
+	c.Assert(m.Spec, qt.Contains, "305x205")
+	c.Assert(m.Width, qt.Equals, 305)
+	c.Assert(m.Height, qt.Equals, 205)
+}
@@ -13,6 +13,18 @@
 
 package page
 
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/spf13/cast"
+)
+
 // AuthorList is a list of all authors and their metadata.
 // Deprecated: Use taxonomies instead.
 type AuthorList map[string]Author
@@ -29,6 +41,165 @@ type Author struct {
 	LongBio     string
 	Email       string
 	Social      AuthorSocial
+
+	// Params holds any custom author metadata. Keys are matched
+	// case insensitively.
+	Params map[string]any
+}
+
+// Param returns the custom metadata value for key, or nil if not set.
+// Deprecated: Use taxonomies instead.
+func (a Author) Param(key string) any {
+	if v, found := a.Params[key]; found {
+		return v
+	}
+	key = strings.ToLower(key)
+	for k, v := range a.Params {
+		if strings.ToLower(k) == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// ParamString returns the custom metadata value for key cast to a string,
+// or "" if not set or not castable.
+// Deprecated: Use taxonomies instead.
+func (a Author) ParamString(key string) string {
+	return cast.ToString(a.Param(key))
+}
+
+// ParamInt returns the custom metadata value for key cast to an int,
+// or 0 if not set or not castable.
+// Deprecated: Use taxonomies instead.
+func (a Author) ParamInt(key string) int {
+	return cast.ToInt(a.Param(key))
+}
+
+// ParamBool returns the custom metadata value for key cast to a bool,
+// or false if not set or not castable.
+// Deprecated: Use taxonomies instead.
+func (a Author) ParamBool(key string) bool {
+	return cast.ToBool(a.Param(key))
+}
+
+// Merge returns a copy of a where other's non-empty fields win. The Social
+// and Params maps are merged key-by-key, with other's entries overriding.
+// Useful when author data comes from more than one source.
+// Deprecated: Use taxonomies instead.
+func (a Author) Merge(other Author) Author {
+	merged := a
+
+	if other.GivenName != "" {
+		merged.GivenName = other.GivenName
+	}
+	if other.FamilyName != "" {
+		merged.FamilyName = other.FamilyName
+	}
+	if other.DisplayName != "" {
+		merged.DisplayName = other.DisplayName
+	}
+	if other.Thumbnail != "" {
+		merged.Thumbnail = other.Thumbnail
+	}
+	if other.Image != "" {
+		merged.Image = other.Image
+	}
+	if other.ShortBio != "" {
+		merged.ShortBio = other.ShortBio
+	}
+	if other.LongBio != "" {
+		merged.LongBio = other.LongBio
+	}
+	if other.Email != "" {
+		merged.Email = other.Email
+	}
+
+	if len(other.Social) > 0 {
+		social := make(AuthorSocial, len(a.Social)+len(other.Social))
+		for k, v := range a.Social {
+			social[k] = v
+		}
+		for k, v := range other.Social {
+			social[k] = v
+		}
+		merged.Social = social
+	}
+
+	if len(other.Params) > 0 {
+		params := make(map[string]any, len(a.Params)+len(other.Params))
+		for k, v := range a.Params {
+			params[k] = v
+		}
+		for k, v := range other.Params {
+			params[k] = v
+		}
+		merged.Params = params
+	}
+
+	return merged
+}
+
+// Equal reports whether a and other hold the same values, including the
+// Social and Params maps.
+// Deprecated: Use taxonomies instead.
+func (a Author) Equal(other Author) bool {
+	return reflect.DeepEqual(a, other)
+}
+
+// mapToAuthors converts a map of author metadata keyed by author id into an
+// AuthorList. A special "_default" entry, if present, acts as a base author
+// whose non-empty fields are inherited by every other author unless
+// overridden; the "_default" entry itself is not part of the result.
+// Deprecated: Use taxonomies instead.
+func mapToAuthors(m map[string]any) AuthorList {
+	var base Author
+	if d, found := m["_default"]; found {
+		base = mapToAuthor(cast.ToStringMap(d))
+	}
+
+	authors := make(AuthorList, len(m))
+	for id, v := range m {
+		if id == "_default" {
+			continue
+		}
+		authors[id] = base.Merge(mapToAuthor(cast.ToStringMap(v)))
+	}
+	return authors
+}
+
+// mapToAuthor converts a map of metadata for a single author into an Author.
+// Unknown keys end up in Params.
+func mapToAuthor(m map[string]any) Author {
+	var a Author
+	for k, v := range m {
+		switch strings.ToLower(k) {
+		case "givenname":
+			a.GivenName = cast.ToString(v)
+		case "familyname":
+			a.FamilyName = cast.ToString(v)
+		case "displayname":
+			a.DisplayName = cast.ToString(v)
+		case "thumbnail":
+			a.Thumbnail = cast.ToString(v)
+		case "image":
+			a.Image = cast.ToString(v)
+		case "shortbio":
+			a.ShortBio = cast.ToString(v)
+		case "longbio":
+			a.LongBio = cast.ToString(v)
+		case "email":
+			a.Email = cast.ToString(v)
+		case "social":
+			a.Social = cast.ToStringMapString(v)
+		default:
+			if a.Params == nil {
+				a.Params = make(map[string]any)
+			}
+			a.Params[k] = v
+		}
+	}
+	return a
 }
 
 // AuthorSocial is a place to put social details per author. These are the
@@ -45,3 +216,149 @@ type Author struct {
 // - skype
 // Deprecated: Use taxonomies instead.
 type AuthorSocial map[string]string
+
+// socialNetwork is one entry in the social network registry: the profile
+// URL pattern for a handle and a regular expression a normalized handle
+// must match to be considered valid.
+type socialNetwork struct {
+	urlTemplate string
+	handleRe    *regexp.Regexp
+}
+
+// socialNetworks maps the standard social keys to their profile URL
+// patterns and allowed handle forms. Custom networks can be added with
+// RegisterSocialNetwork.
+var socialNetworks = map[string]socialNetwork{
+	"github":    {"https://github.com/%s", regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]{0,38}$`)},
+	"facebook":  {"https://www.facebook.com/%s", regexp.MustCompile(`^[a-zA-Z0-9.]{5,50}$`)},
+	"twitter":   {"https://twitter.com/%s", regexp.MustCompile(`^[a-zA-Z0-9_]{1,15}$`)},
+	"pinterest": {"https://www.pinterest.com/%s", regexp.MustCompile(`^[a-zA-Z0-9_]{3,30}$`)},
+	"instagram": {"https://www.instagram.com/%s", regexp.MustCompile(`^[a-zA-Z0-9._]{1,30}$`)},
+	"youtube":   {"https://www.youtube.com/user/%s", regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)},
+	"linkedin":  {"https://www.linkedin.com/in/%s", regexp.MustCompile(`^[a-zA-Z0-9-]{3,100}$`)},
+	"skype":     {"skype:%s?userinfo", regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.,-]{5,31}$`)},
+}
+
+// RegisterSocialNetwork adds or replaces a network in the social network
+// registry. urlTemplate takes the handle via %s; handlePattern, if
+// non-empty, is a regular expression a normalized handle must fully match
+// to pass Validate. It returns an error if the pattern doesn't compile.
+// Deprecated: Use taxonomies instead.
+func RegisterSocialNetwork(key, urlTemplate, handlePattern string) error {
+	var re *regexp.Regexp
+	if handlePattern != "" {
+		var err error
+		re, err = regexp.Compile("^(?:" + handlePattern + ")$")
+		if err != nil {
+			return err
+		}
+	}
+	socialNetworks[strings.ToLower(key)] = socialNetwork{urlTemplate, re}
+	return nil
+}
+
+// normalizeSocial cleans up a social handle for validation and URL
+// building: surrounding white space, a leading @ or + sigil, any trailing
+// URL query or fragment and a trailing slash are stripped.
+func normalizeSocial(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(v, "@")
+	v = strings.TrimPrefix(v, "+")
+	if i := strings.IndexAny(v, "?#"); i != -1 {
+		v = v[:i]
+	}
+	return strings.TrimSuffix(v, "/")
+}
+
+// Validate returns a copy of s with handles that don't match their
+// network's allowed pattern dropped, logging a warning for each, so an
+// obviously malformed handle doesn't end up as a broken profile URL.
+// Values that already are URLs, keys without a registered pattern and
+// valid handles are kept unchanged.
+// Deprecated: Use taxonomies instead.
+func (s AuthorSocial) Validate(logger loggers.Logger) AuthorSocial {
+	res := make(AuthorSocial, len(s))
+	for k, v := range s {
+		n, found := socialNetworks[strings.ToLower(k)]
+		if found && n.handleRe != nil && !strings.Contains(v, "://") {
+			if !n.handleRe.MatchString(normalizeSocial(v)) {
+				logger.Warnf("invalid %s handle %q in author social; dropping it", k, v)
+				continue
+			}
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// URL resolves the handle stored for key into a profile URL. Values that
+// already are URLs and keys without a known URL pattern are returned as is;
+// an unset key returns "".
+// Deprecated: Use taxonomies instead.
+func (s AuthorSocial) URL(key string) string {
+	v, found := s[key]
+	if !found || v == "" {
+		return ""
+	}
+	if strings.Contains(v, "://") {
+		return v
+	}
+	n, found := socialNetworks[strings.ToLower(key)]
+	if !found {
+		return v
+	}
+	return fmt.Sprintf(n.urlTemplate, normalizeSocial(v))
+}
+
+// authorJSON is the serializable representation of one author, used by
+// AuthorList.MarshalJSON.
+type authorJSON struct {
+	ID          string            `json:"id"`
+	GivenName   string            `json:"givenName,omitempty"`
+	FamilyName  string            `json:"familyName,omitempty"`
+	DisplayName string            `json:"displayName,omitempty"`
+	Thumbnail   string            `json:"thumbnail,omitempty"`
+	Image       string            `json:"image,omitempty"`
+	ShortBio    string            `json:"shortBio,omitempty"`
+	LongBio     string            `json:"longBio,omitempty"`
+	Email       string            `json:"email,omitempty"`
+	Social      map[string]string `json:"social,omitempty"`
+}
+
+// MarshalJSON encodes the list as a JSON array ordered by author id, so the
+// output is deterministic across builds, e.g. for a data API. The social
+// entries hold the resolved profile URLs (see AuthorSocial.URL) and empty
+// fields are omitted.
+// Deprecated: Use taxonomies instead.
+func (al AuthorList) MarshalJSON() ([]byte, error) {
+	ids := make([]string, 0, len(al))
+	for id := range al {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make([]authorJSON, 0, len(al))
+	for _, id := range ids {
+		a := al[id]
+		aj := authorJSON{
+			ID:          id,
+			GivenName:   a.GivenName,
+			FamilyName:  a.FamilyName,
+			DisplayName: a.DisplayName,
+			Thumbnail:   a.Thumbnail,
+			Image:       a.Image,
+			ShortBio:    a.ShortBio,
+			LongBio:     a.LongBio,
+			Email:       a.Email,
+		}
+		if len(a.Social) > 0 {
+			social := make(map[string]string, len(a.Social))
+			for k := range a.Social {
+				social[k] = a.Social.URL(k)
+			}
+			aj.Social = social
+		}
+		out = append(out, aj)
+	}
+	return json.Marshal(out)
+}
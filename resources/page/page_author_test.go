@@ -0,0 +1,212 @@
+// Copyright 2024 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/loggers"
+)
+
+func TestAuthorParam(t *testing.T) {
+	c := qt.New(t)
+
+	a := Author{
+		DisplayName: "Jane Doe",
+		Params: map[string]any{
+			"Affiliation": "Example Org",
+			"rank":        "7",
+			"active":      true,
+		},
+	}
+
+	c.Assert(a.Param("affiliation"), qt.Equals, "Example Org")
+	c.Assert(a.Param("Affiliation"), qt.Equals, "Example Org")
+	c.Assert(a.Param("missing"), qt.IsNil)
+	c.Assert(a.ParamString("affiliation"), qt.Equals, "Example Org")
+	c.Assert(a.ParamString("missing"), qt.Equals, "")
+	c.Assert(a.ParamInt("rank"), qt.Equals, 7)
+	c.Assert(a.ParamBool("active"), qt.Equals, true)
+	c.Assert(a.ParamBool("missing"), qt.Equals, false)
+}
+
+func TestAuthorMerge(t *testing.T) {
+	c := qt.New(t)
+
+	base := Author{
+		DisplayName: "Jane Doe",
+		Email:       "jane@example.org",
+		Social: AuthorSocial{
+			"website": "https://example.org",
+			"github":  "janedoe",
+		},
+		Params: map[string]any{"org": "Example Org"},
+	}
+
+	// Only social links differ.
+	merged := base.Merge(Author{
+		Social: AuthorSocial{"github": "jane-doe", "twitter": "janedoe"},
+	})
+	c.Assert(merged.DisplayName, qt.Equals, "Jane Doe")
+	c.Assert(merged.Email, qt.Equals, "jane@example.org")
+	c.Assert(merged.Social, qt.DeepEquals, AuthorSocial{
+		"website": "https://example.org",
+		"github":  "jane-doe",
+		"twitter": "janedoe",
+	})
+	c.Assert(merged.Params, qt.DeepEquals, map[string]any{"org": "Example Org"})
+
+	// Non-empty fields win; empty ones don't clear.
+	merged = base.Merge(Author{DisplayName: "J. Doe"})
+	c.Assert(merged.DisplayName, qt.Equals, "J. Doe")
+	c.Assert(merged.Email, qt.Equals, "jane@example.org")
+
+	// The receiver is left untouched.
+	c.Assert(base.Social["github"], qt.Equals, "janedoe")
+
+	c.Assert(base.Equal(base), qt.IsTrue)
+	c.Assert(base.Equal(merged), qt.IsFalse)
+}
+
+func TestMapToAuthors(t *testing.T) {
+	c := qt.New(t)
+
+	authors := mapToAuthors(map[string]any{
+		"_default": map[string]any{
+			"shortBio": "Works at Example Org",
+			"social":   map[string]any{"website": "https://example.org"},
+			"org":      "Example Org",
+		},
+		"jane": map[string]any{
+			"displayName": "Jane Doe",
+			"email":       "jane@example.org",
+		},
+		"john": map[string]any{
+			"displayName": "John Doe",
+			"shortBio":    "Freelancer",
+			"social":      map[string]any{"website": "https://john.example.org"},
+		},
+	})
+
+	c.Assert(authors, qt.HasLen, 2)
+
+	// The default entry is inherited ...
+	jane := authors["jane"]
+	c.Assert(jane.DisplayName, qt.Equals, "Jane Doe")
+	c.Assert(jane.Email, qt.Equals, "jane@example.org")
+	c.Assert(jane.ShortBio, qt.Equals, "Works at Example Org")
+	c.Assert(jane.Social["website"], qt.Equals, "https://example.org")
+	c.Assert(jane.Param("org"), qt.Equals, "Example Org")
+
+	// ... unless overridden per author.
+	john := authors["john"]
+	c.Assert(john.ShortBio, qt.Equals, "Freelancer")
+	c.Assert(john.Social["website"], qt.Equals, "https://john.example.org")
+	c.Assert(john.Param("org"), qt.Equals, "Example Org")
+
+	// No default entry.
+	authors = mapToAuthors(map[string]any{
+		"jane": map[string]any{"displayName": "Jane Doe"},
+	})
+	c.Assert(authors, qt.HasLen, 1)
+	c.Assert(authors["jane"].DisplayName, qt.Equals, "Jane Doe")
+}
+
+func TestAuthorSocialURL(t *testing.T) {
+	c := qt.New(t)
+
+	s := AuthorSocial{
+		"github":   "janedoe",
+		"twitter":  "jane_doe",
+		"website":  "https://jane.example.org",
+		"mastodon": "@jane@example.org",
+	}
+
+	c.Assert(s.URL("github"), qt.Equals, "https://github.com/janedoe")
+	c.Assert(s.URL("twitter"), qt.Equals, "https://twitter.com/jane_doe")
+	c.Assert(s.URL("website"), qt.Equals, "https://jane.example.org")
+	c.Assert(s.URL("mastodon"), qt.Equals, "@jane@example.org")
+	c.Assert(s.URL("missing"), qt.Equals, "")
+
+	// Handles are normalized before the URL is built.
+	c.Assert(AuthorSocial{"twitter": "@jane_doe"}.URL("twitter"), qt.Equals, "https://twitter.com/jane_doe")
+	c.Assert(AuthorSocial{"github": "janedoe/"}.URL("github"), qt.Equals, "https://github.com/janedoe")
+}
+
+func TestAuthorSocialValidate(t *testing.T) {
+	c := qt.New(t)
+
+	logger := loggers.NewDefault()
+
+	s := AuthorSocial{
+		"github":   "janedoe",
+		"twitter":  "@jane_doe",
+		"website":  "https://jane.example.org",
+		"mastodon": "@jane@example.org",
+		"facebook": "no spaces allowed",
+		"linkedin": "x",
+	}
+
+	// Valid handles, URLs and unregistered networks pass through
+	// unchanged; malformed handles are dropped.
+	c.Assert(s.Validate(logger), qt.DeepEquals, AuthorSocial{
+		"github":   "janedoe",
+		"twitter":  "@jane_doe",
+		"website":  "https://jane.example.org",
+		"mastodon": "@jane@example.org",
+	})
+
+	// Values that already are URLs skip handle validation.
+	s = AuthorSocial{"github": "https://github.com/janedoe"}
+	c.Assert(s.Validate(logger), qt.DeepEquals, s)
+
+	// Custom networks supply their own pattern.
+	c.Assert(RegisterSocialNetwork("gitea", "https://gitea.example.org/%s", `[a-z0-9-]+`), qt.IsNil)
+	s = AuthorSocial{"gitea": "jane-doe"}
+	c.Assert(s.Validate(logger), qt.DeepEquals, s)
+	c.Assert(s.URL("gitea"), qt.Equals, "https://gitea.example.org/jane-doe")
+	c.Assert(AuthorSocial{"gitea": "Jane Doe"}.Validate(logger), qt.HasLen, 0)
+
+	// A pattern that doesn't compile is an error.
+	c.Assert(RegisterSocialNetwork("broken", "https://broken.example.org/%s", `[`), qt.IsNotNil)
+}
+
+func TestAuthorListMarshalJSON(t *testing.T) {
+	c := qt.New(t)
+
+	al := AuthorList{
+		"zoe": {
+			DisplayName: "Zoe Zed",
+		},
+		"jane": {
+			DisplayName: "Jane Doe",
+			Email:       "jane@example.org",
+			Social: AuthorSocial{
+				"github":  "janedoe",
+				"website": "https://jane.example.org",
+			},
+		},
+	}
+
+	b, err := json.Marshal(al)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, `[{"id":"jane","displayName":"Jane Doe","email":"jane@example.org","social":{"github":"https://github.com/janedoe","website":"https://jane.example.org"}},{"id":"zoe","displayName":"Zoe Zed"}]`)
+
+	// Deterministic across marshals.
+	b2, err := json.Marshal(al)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b2), qt.Equals, string(b))
+}
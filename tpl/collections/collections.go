@@ -25,6 +25,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mitchellh/hashstructure"
+	"golang.org/x/text/collate"
+
 	"github.com/gohugoio/hugo/common/collections"
 	"github.com/gohugoio/hugo/common/hugo"
 	"github.com/gohugoio/hugo/common/maps"
@@ -55,6 +58,15 @@ type Namespace struct {
 	loc      *time.Location
 	sortComp *compare.Namespace
 	deps     *deps.Deps
+
+	// collator, if set, makes the string ordering operators in where
+	// compare using locale collation rules. Set on a per-call copy of the
+	// namespace by WhereCollated; nil for code-point ordering.
+	collator *collate.Collator
+
+	// normalizeNFC makes where normalize strings to NFC before comparing
+	// them. Set on a per-call copy of the namespace by WhereNormalized.
+	normalizeNFC bool
 }
 
 // After returns all the items after the first n items in list l.
@@ -766,6 +778,78 @@ func (ns *Namespace) Uniq(l any) (any, error) {
 	return slice.Interface(), nil
 }
 
+// UniqByKeys returns l with duplicate elements removed, where uniqueness is
+// defined by the combination of the values resolved from the given key
+// paths, e.g. "Author", "Params.year". The first element per combination is
+// kept and the input order is preserved. The values are normalized before
+// comparison, so e.g. numbers of different types that are numerically equal
+// count as the same. An element for which a key path does not resolve is an
+// error.
+func (ns *Namespace) UniqByKeys(ctx context.Context, l any, keys ...string) (any, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key must be provided")
+	}
+
+	seqv, isNil := indirect(reflect.ValueOf(l))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value")
+	}
+
+	var slice reflect.Value
+	switch seqv.Kind() {
+	case reflect.Slice:
+		slice = reflect.MakeSlice(seqv.Type(), 0, 0)
+	case reflect.Array:
+		slice = reflect.MakeSlice(reflect.SliceOf(seqv.Type().Elem()), 0, 0)
+	default:
+		return nil, fmt.Errorf("type %T not supported", l)
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+	paths := make([][]string, len(keys))
+	for i, k := range keys {
+		paths[i] = strings.Split(strings.Trim(k, "."), ".")
+	}
+
+	seen := make(map[uint64]bool)
+
+	for i := 0; i < seqv.Len(); i++ {
+		composite := make([]any, len(paths))
+		for j, path := range paths {
+			vvv := seqv.Index(i)
+			for k, elemName := range path {
+				var err error
+				vvv, err = evaluateSubElem(ctxv, vvv, elemName)
+				if err != nil {
+					return nil, fmt.Errorf("element %d: key %q: %w", i, keys[j], err)
+				}
+				if k < len(path)-1 && vvv.IsValid() {
+					if params, ok := vvv.Interface().(maps.Params); ok {
+						vvv = reflect.ValueOf(params.GetNested(path[k+1:]...))
+						break
+					}
+				}
+			}
+			vvv, _ = indirect(vvv)
+			if !vvv.IsValid() {
+				return nil, fmt.Errorf("element %d: key %q did not resolve", i, keys[j])
+			}
+			composite[j] = normalize(vvv)
+		}
+
+		h, err := hashstructure.Hash(composite, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[h] {
+			seen[h] = true
+			slice = reflect.Append(slice, seqv.Index(i))
+		}
+	}
+
+	return slice.Interface(), nil
+}
+
 // KeyVals creates a key and values wrapper.
 func (ns *Namespace) KeyVals(key any, values ...any) (types.KeyValues, error) {
 	return types.KeyValues{Key: key, Values: values}, nil
@@ -882,6 +882,42 @@ func TestUniq(t *testing.T) {
 	}
 }
 
+func TestUniqByKeys(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+	ns := newNs()
+	ctx := context.Background()
+
+	books := []map[string]any{
+		{"author": "Jane", "year": 2020, "title": "A"},
+		{"author": "Jane", "year": 2020, "title": "B"},
+		{"author": "Jane", "year": 2021, "title": "C"},
+		{"author": "John", "year": 2020, "title": "D"},
+	}
+
+	result, err := ns.UniqByKeys(ctx, books, "author", "year")
+	c.Assert(err, qt.IsNil)
+	var titles []string
+	for _, b := range result.([]map[string]any) {
+		titles = append(titles, b["title"].(string))
+	}
+	c.Assert(titles, qt.DeepEquals, []string{"A", "C", "D"})
+
+	// Numerically equal values count as the same.
+	nums := []map[string]any{{"v": 1, "id": "a"}, {"v": 1.0, "id": "b"}, {"v": 2, "id": "c"}}
+	result, err = ns.UniqByKeys(ctx, nums, "v")
+	c.Assert(err, qt.IsNil)
+	c.Assert(result, qt.HasLen, 2)
+
+	// A key that does not resolve is an error.
+	_, err = ns.UniqByKeys(ctx, books, "author", "nope")
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	// At least one key is required.
+	_, err = ns.UniqByKeys(ctx, books)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
 func (x *TstX) TstRp() string {
 	return "r" + x.A
 }
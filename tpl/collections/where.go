@@ -17,24 +17,670 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+
 	"github.com/gohugoio/hugo/common/hreflect"
 	"github.com/gohugoio/hugo/common/hstrings"
 	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/compare"
 )
 
-// Where returns a filtered subset of collection c.
-func (ns *Namespace) Where(ctx context.Context, c, key any, args ...any) (any, error) {
+// Rangeable is implemented by map-like collections such as sync.Map that
+// expose their entries through a Range callback rather than as a native
+// map. Where materializes them into a plain map before filtering.
+type Rangeable interface {
+	Range(f func(key, value any) bool)
+}
+
+// Where returns a filtered subset of collection c. An empty or "." key
+// matches on the element itself, which allows filtering plain value
+// collections, e.g. a []string.
+func (ns *Namespace) Where(ctx context.Context, c, key any, args ...any) (any, error) {
+	if r, ok := c.(Rangeable); ok {
+		c = rangeableToMap(r)
+	}
+
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+
+	mv, op, err := parseWhereArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		return ns.checkWhereArray(ctxv, seqv, kv, mv, path, op)
+	case reflect.Map:
+		return ns.checkWhereMap(ctxv, seqv, kv, mv, path, op)
+	case reflect.Chan:
+		slicev, err := drainChannel(seqv)
+		if err != nil {
+			return nil, err
+		}
+		return ns.checkWhereArray(ctxv, slicev, kv, mv, path, op)
+	default:
+		return nil, fmt.Errorf("can't iterate over %T", c)
+	}
+}
+
+// WhereCollated is like Where, but the string ordering operators (<, <=,
+// >, >=) compare using the collation rules for the given language tag, so
+// e.g. accented characters sort per locale instead of by code point:
+//
+//	{{ $r := collections.WhereCollated $cities "Name" "<" "Münster" "de" }}
+//
+// The default Where keeps code-point ordering for performance and
+// compatibility.
+func (ns *Namespace) WhereCollated(ctx context.Context, c, key any, op string, match any, lang string) (any, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid language tag %q: %w", lang, err)
+	}
+
+	// The collator is not safe for concurrent use, so give this call its
+	// own instance on a copy of the namespace.
+	nsc := *ns
+	nsc.collator = collate.New(tag)
+
+	return nsc.Where(ctx, c, key, op, match)
+}
+
+// WhereNormalized is like Where, but normalizes both the field value and
+// the match to NFC before string comparison, so composed and decomposed
+// accents (e.g. "é" as one vs. two code points) compare equal. The default
+// Where compares strings as is for performance.
+func (ns *Namespace) WhereNormalized(ctx context.Context, c, key any, args ...any) (any, error) {
+	nsc := *ns
+	nsc.normalizeNFC = true
+
+	return nsc.Where(ctx, c, key, args...)
+}
+
+// normString returns s normalized to NFC with WhereNormalized, otherwise s
+// unchanged.
+func (ns *Namespace) normString(s string) string {
+	if !ns.normalizeNFC {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// compareStrings compares a and b like strings.Compare, except with a
+// collator set (see WhereCollated), where locale collation rules apply.
+func (ns *Namespace) compareStrings(a, b string) int {
+	if ns.collator == nil {
+		return strings.Compare(a, b)
+	}
+	return ns.collator.CompareString(a, b)
+}
+
+// whereFloatEpsilon is the tolerance used by the "approx" operator when
+// comparing floats.
+const whereFloatEpsilon = 1e-9
+
+// kindNamePredicates maps the kind names accepted by the "iskind" operator
+// to their matching reflect kinds. The numeric names match any width, so
+// "int" covers int8 through int64, "float" both float32 and float64, etc.
+var kindNamePredicates = map[string]func(reflect.Kind) bool{
+	"bool":   func(k reflect.Kind) bool { return k == reflect.Bool },
+	"string": func(k reflect.Kind) bool { return k == reflect.String },
+	"int":    func(k reflect.Kind) bool { return k >= reflect.Int && k <= reflect.Int64 },
+	"uint":   func(k reflect.Kind) bool { return k >= reflect.Uint && k <= reflect.Uint64 },
+	"float":  func(k reflect.Kind) bool { return k == reflect.Float32 || k == reflect.Float64 },
+	"map":    func(k reflect.Kind) bool { return k == reflect.Map },
+	"slice":  func(k reflect.Kind) bool { return k == reflect.Slice },
+	"array":  func(k reflect.Kind) bool { return k == reflect.Array },
+	"struct": func(k reflect.Kind) bool { return k == reflect.Struct },
+}
+
+// maxChannelDrain caps how many elements Where will receive from a channel,
+// as a safeguard against unbounded draining.
+const maxChannelDrain = 100000
+
+// drainChannel receives every element from chv until it is closed and
+// returns them as a slice. The channel must be closed by the sender or the
+// receive will block.
+func drainChannel(chv reflect.Value) (reflect.Value, error) {
+	if chv.Type().ChanDir()&reflect.RecvDir == 0 {
+		return zero, errors.New("can't receive from a send-only channel")
+	}
+	rv := reflect.MakeSlice(reflect.SliceOf(chv.Type().Elem()), 0, 0)
+	for {
+		v, ok := chv.Recv()
+		if !ok {
+			return rv, nil
+		}
+		if rv.Len() >= maxChannelDrain {
+			return zero, fmt.Errorf("channel exceeds the maximum of %d elements", maxChannelDrain)
+		}
+		rv = reflect.Append(rv, v)
+	}
+}
+
+// WhereLimit is like Where, but stops scanning a slice once limit matches
+// have been collected, which saves work when only the first few matches of
+// a huge collection are needed, e.g. for a "latest 5 matching posts" list.
+// A negative limit behaves like Where. For maps the limit is ignored and
+// every entry is checked, as map iteration order is undefined.
+func (ns *Namespace) WhereLimit(ctx context.Context, c any, limit int, key any, args ...any) (any, error) {
+	if r, ok := c.(Rangeable); ok {
+		c = rangeableToMap(r)
+	}
+
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+
+	mv, op, err := parseWhereArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		return ns.checkWhereArrayLimit(ctxv, seqv, kv, mv, path, op, limit)
+	case reflect.Map:
+		return ns.checkWhereMap(ctxv, seqv, kv, mv, path, op)
+	case reflect.Chan:
+		slicev, err := drainChannel(seqv)
+		if err != nil {
+			return nil, err
+		}
+		return ns.checkWhereArrayLimit(ctxv, slicev, kv, mv, path, op, limit)
+	default:
+		return nil, fmt.Errorf("can't iterate over %T", c)
+	}
+}
+
+// WhereSorted is like Where, but sorts the filtered result by the field
+// sortKey in the given order ("asc" or "desc") before returning it, fusing
+// the common filter-then-sort template pattern into one call. The sort key
+// resolves like the filter key and must yield a number, a string or a
+// time.Time for every kept element; the sort is stable. Only arrays and
+// slices are supported.
+func (ns *Namespace) WhereSorted(ctx context.Context, c, key any, args ...any) (any, error) {
+	if len(args) < 3 {
+		return nil, errors.New("WhereSorted requires a match value, a sort key and a sort order")
+	}
+	sortKey, ok := args[len(args)-2].(string)
+	if !ok {
+		return nil, errors.New("the sort key must be a string")
+	}
+	sortOrder, ok := args[len(args)-1].(string)
+	if !ok {
+		return nil, errors.New("the sort order must be a string")
+	}
+	sortOrder = strings.ToLower(sortOrder)
+	if sortOrder != "asc" && sortOrder != "desc" {
+		return nil, fmt.Errorf("the sort order must be %q or %q", "asc", "desc")
+	}
+
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+	if seqv.Kind() != reflect.Array && seqv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't iterate over %T", c)
+	}
+
+	r, err := ns.Where(ctx, c, key, args[:len(args)-2]...)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(r)
+	ctxv := reflect.ValueOf(ctx)
+	kv := reflect.ValueOf(sortKey)
+	path := strings.Split(strings.Trim(sortKey, "."), ".")
+
+	// Decorate-sort-undecorate, so the sort key is evaluated once per
+	// element instead of once per comparison.
+	type keyed struct {
+		elem      reflect.Value
+		fv        float64
+		sv        string
+		isNumeric bool
+	}
+	ks := make([]keyed, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		k := keyed{elem: rv.Index(i)}
+		vvv, _ := indirect(whereElemValue(ctxv, rv.Index(i), kv, path))
+		if !vvv.IsValid() {
+			return nil, fmt.Errorf("element %d: sort key %q did not resolve", i, sortKey)
+		}
+		switch {
+		case vvv.Kind() == reflect.Struct && hreflect.IsTime(vvv.Type()):
+			k.fv = float64(ns.toTimeUnix(vvv))
+			k.isNumeric = true
+		case isNumber(vvv.Kind()):
+			k.fv, _ = toFloat(vvv)
+			k.isNumeric = true
+		default:
+			s, err := toString(vvv)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: sort key %q is not a number, a string or a time.Time", i, sortKey)
+			}
+			k.sv = s
+		}
+		ks[i] = k
+	}
+
+	less := func(i, j int) bool {
+		if ks[i].isNumeric && ks[j].isNumeric {
+			return ks[i].fv < ks[j].fv
+		}
+		return ns.compareStrings(ks[i].sv, ks[j].sv) < 0
+	}
+	if sortOrder == "desc" {
+		sort.SliceStable(ks, func(i, j int) bool { return less(j, i) })
+	} else {
+		sort.SliceStable(ks, less)
+	}
+
+	sorted := reflect.MakeSlice(rv.Type(), 0, len(ks))
+	for _, k := range ks {
+		sorted = reflect.Append(sorted, k.elem)
+	}
+	return sorted.Interface(), nil
+}
+
+// CountWhere returns the number of elements of c that Where with the same
+// arguments would keep, without materializing the filtered collection. For
+// maps it counts the entries that would be kept.
+func (ns *Namespace) CountWhere(ctx context.Context, c, key any, args ...any) (int, error) {
+	if r, ok := c.(Rangeable); ok {
+		c = rangeableToMap(r)
+	}
+
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return 0, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+
+	mv, op, err := parseWhereArgs(args...)
+	if err != nil {
+		return 0, err
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		return ns.countWhereArray(ctxv, seqv, kv, mv, path, op)
+	case reflect.Map:
+		return ns.countWhereMap(ctxv, seqv, kv, mv, path, op)
+	case reflect.Chan:
+		slicev, err := drainChannel(seqv)
+		if err != nil {
+			return 0, err
+		}
+		return ns.countWhereArray(ctxv, slicev, kv, mv, path, op)
+	default:
+		return 0, fmt.Errorf("can't iterate over %T", c)
+	}
+}
+
+// rangeableToMap materializes the entries of r into a plain map.
+func rangeableToMap(r Rangeable) map[any]any {
+	m := make(map[any]any)
+	r.Range(func(k, v any) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
+
+// WhereAny is like Where, but also accepts a single (non-collection) value,
+// which is treated as a one-element sequence; the result is then a slice
+// with one or zero elements. This saves template authors a type check when
+// a context sometimes holds a single item and sometimes a list. Collections
+// are filtered as by Where.
+func (ns *Namespace) WhereAny(ctx context.Context, c, key any, args ...any) (any, error) {
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Chan:
+		return ns.Where(ctx, c, key, args...)
+	}
+
+	slicev := reflect.MakeSlice(reflect.SliceOf(seqv.Type()), 0, 1)
+	slicev = reflect.Append(slicev, seqv)
+	return ns.Where(ctx, slicev.Interface(), key, args...)
+}
+
+// Partition splits collection c into the elements matching the condition and
+// the elements not matching it, in one pass. The condition arguments are the
+// same as for Where, and input order is preserved in both partitions. For a
+// map, entries go to the matched partition if any of their elements match.
+func (ns *Namespace) Partition(ctx context.Context, c, key any, args ...any) (matched, unmatched any, err error) {
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+
+	mv, op, err := parseWhereArgs(args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		return ns.partitionWhereArray(ctxv, seqv, kv, mv, path, op)
+	case reflect.Map:
+		return ns.partitionWhereMap(ctxv, seqv, kv, mv, path, op)
+	default:
+		return nil, nil, fmt.Errorf("can't iterate over %T", c)
+	}
+}
+
+// partitionWhereArray handles the partition logic when the seqv value is an
+// Array or Slice.
+func (ns *Namespace) partitionWhereArray(ctxv, seqv, kv, mv reflect.Value, path []string, op string) (any, any, error) {
+	mrv := reflect.MakeSlice(seqv.Type(), 0, 0)
+	urv := reflect.MakeSlice(seqv.Type(), 0, 0)
+
+	for i := 0; i < seqv.Len(); i++ {
+		rvv := seqv.Index(i)
+		vvv := whereElemValue(ctxv, rvv, kv, path)
+
+		if ok, err := ns.checkCondition(vvv, mv, op); ok {
+			mrv = reflect.Append(mrv, rvv)
+		} else if err != nil {
+			return nil, nil, err
+		} else {
+			urv = reflect.Append(urv, rvv)
+		}
+	}
+	return mrv.Interface(), urv.Interface(), nil
+}
+
+// partitionWhereMap handles the partition logic when the seqv value is a Map.
+func (ns *Namespace) partitionWhereMap(ctxv, seqv, kv, mv reflect.Value, path []string, op string) (any, any, error) {
+	mrv := reflect.MakeMap(seqv.Type())
+	urv := reflect.MakeMap(seqv.Type())
+	for _, k := range seqv.MapKeys() {
+		elemv := seqv.MapIndex(k)
+		elemvv, isNil := indirect(elemv)
+		if isNil {
+			continue
+		}
+
+		switch elemvv.Kind() {
+		case reflect.Array, reflect.Slice:
+			r, err := ns.checkWhereArray(ctxv, elemvv, kv, mv, path, op)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if rr := reflect.ValueOf(r); rr.Kind() == reflect.Slice && rr.Len() > 0 {
+				mrv.SetMapIndex(k, elemv)
+			} else {
+				urv.SetMapIndex(k, elemv)
+			}
+		default:
+			urv.SetMapIndex(k, elemv)
+		}
+	}
+	return mrv.Interface(), urv.Interface(), nil
+}
+
+// isElemSelfPath reports whether the parsed key path refers to the element
+// itself, i.e. the key was empty or ".".
+func isElemSelfPath(path []string) bool {
+	return len(path) == 0 || (len(path) == 1 && path[0] == "")
+}
+
+// whereElemValue evaluates the value to match against for one element of the
+// sequence, following path for string keys and doing a map lookup otherwise.
+func whereElemValue(ctxv, rvv, kv reflect.Value, path []string) reflect.Value {
+	var vvv reflect.Value
+	if kv.Kind() == reflect.String {
+		if isElemSelfPath(path) {
+			// An empty or "." key means the element itself, which allows
+			// filtering plain value collections, e.g. a []string.
+			return rvv
+		}
+		if params, ok := rvv.Interface().(maps.Params); ok {
+			vvv = reflect.ValueOf(params.GetNested(path...))
+		} else {
+			vvv = rvv
+			for i, elemName := range path {
+				var err error
+				vvv, err = evaluateSubElem(ctxv, vvv, elemName)
+
+				if err != nil {
+					continue
+				}
+
+				if i < len(path)-1 && vvv.IsValid() {
+					if params, ok := vvv.Interface().(maps.Params); ok {
+						// The current path element is the map itself, .Params.
+						vvv = reflect.ValueOf(params.GetNested(path[i+1:]...))
+						break
+					}
+				}
+			}
+		}
+	} else {
+		vv, _ := indirect(rvv)
+		if vv.Kind() == reflect.Map && kv.Type().AssignableTo(vv.Type().Key()) {
+			vvv = vv.MapIndex(kv)
+		}
+	}
+	return vvv
+}
+
+// WhereMapValue filters c by testing every value of the map-valued field
+// key, keeping the element if any value satisfies the condition. Useful for
+// data like .Params.ratings = {"editor": 5, "reader": 4} where the map keys
+// aren't known up front. It is an error if the field is not a map. Only
+// arrays and slices are supported.
+func (ns *Namespace) WhereMapValue(ctx context.Context, c, key any, args ...any) (any, error) {
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+	if seqv.Kind() != reflect.Array && seqv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't iterate over %T", c)
+	}
+
+	mv, op, err := parseWhereArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	rv := reflect.MakeSlice(seqv.Type(), 0, 0)
+
+	for i := 0; i < seqv.Len(); i++ {
+		rvv := seqv.Index(i)
+		vvv, _ := indirect(whereElemValue(ctxv, rvv, kv, path))
+
+		if !vvv.IsValid() {
+			continue
+		}
+		if vvv.Kind() != reflect.Map {
+			return nil, fmt.Errorf("element %d: key %q is not a map, but %s", i, key, vvv.Kind())
+		}
+
+		for _, k := range vvv.MapKeys() {
+			ok, err := ns.checkCondition(vvv.MapIndex(k), mv, op)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			if ok {
+				rv = reflect.Append(rv, rvv)
+				break
+			}
+		}
+	}
+	return rv.Interface(), nil
+}
+
+// WhereMapValueMatch filters c by matching pattern against every value of
+// the map-valued field key, keeping the element if any value matches. The
+// compiled pattern is cached, as for the findRE template function. Map
+// values that aren't strings are skipped. Useful for filtering structured
+// metadata like i18n string maps by content. It is an error if the field is
+// not a map. Only arrays and slices are supported.
+func (ns *Namespace) WhereMapValueMatch(ctx context.Context, c, key any, pattern string) (any, error) {
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+	}
+	if seqv.Kind() != reflect.Array && seqv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't iterate over %T", c)
+	}
+
+	re, err := hstrings.GetOrCompileRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	rv := reflect.MakeSlice(seqv.Type(), 0, 0)
+
+	for i := 0; i < seqv.Len(); i++ {
+		rvv := seqv.Index(i)
+		vvv, _ := indirect(whereElemValue(ctxv, rvv, kv, path))
+
+		if !vvv.IsValid() {
+			continue
+		}
+		if vvv.Kind() != reflect.Map {
+			return nil, fmt.Errorf("element %d: key %q is not a map, but %s", i, key, vvv.Kind())
+		}
+
+		for _, k := range vvv.MapKeys() {
+			s, err := toString(vvv.MapIndex(k))
+			if err != nil {
+				continue
+			}
+			if re.MatchString(s) {
+				rv = reflect.Append(rv, rvv)
+				break
+			}
+		}
+	}
+	return rv.Interface(), nil
+}
+
+// WhereInBy filters c, keeping the elements whose field key is "in" the
+// values of matchKey resolved on each element of match. The key path on the
+// match side mirrors the one on the element side, which enables joining two
+// collections in templates, e.g. keeping the pages whose .Params.author is
+// among the ids of a data slice. It is an error if matchKey doesn't resolve
+// for an element of match.
+func (ns *Namespace) WhereInBy(ctx context.Context, c, key, match any, matchKey string) (any, error) {
+	mseqv, isNil := indirect(reflect.ValueOf(match))
+	if isNil {
+		return nil, errors.New("can't evaluate a nil match collection")
+	}
+	if mseqv.Kind() != reflect.Array && mseqv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("match must be an array or a slice, got %T", match)
+	}
+
+	ctxv := reflect.ValueOf(ctx)
+	path := strings.Split(strings.Trim(matchKey, "."), ".")
+
+	mvs := make([]any, mseqv.Len())
+	for i := 0; i < mseqv.Len(); i++ {
+		vvv := mseqv.Index(i)
+		for j, elemName := range path {
+			var err error
+			vvv, err = evaluateSubElem(ctxv, vvv, elemName)
+			if err != nil {
+				return nil, fmt.Errorf("match element %d: key %q: %w", i, matchKey, err)
+			}
+			if j < len(path)-1 && vvv.IsValid() {
+				if params, ok := vvv.Interface().(maps.Params); ok {
+					vvv = reflect.ValueOf(params.GetNested(path[j+1:]...))
+					break
+				}
+			}
+		}
+		if !vvv.IsValid() {
+			return nil, fmt.Errorf("match element %d: key %q did not resolve", i, matchKey)
+		}
+		mvs[i] = vvv.Interface()
+	}
+
+	return ns.Where(ctx, c, key, "in", mvs)
+}
+
+// WhereLenient is a lenient variant of Where: elements that fail to evaluate
+// or compare are skipped instead of aborting the whole operation. The errors
+// for the skipped elements are returned alongside the filtered result so the
+// caller can surface them as diagnostics. Only arrays and slices are
+// supported.
+func (ns *Namespace) WhereLenient(ctx context.Context, c, key any, args ...any) (any, []error) {
 	seqv, isNil := indirect(reflect.ValueOf(c))
 	if isNil {
-		return nil, errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())
+		return nil, []error{errors.New("can't iterate over a nil value of type " + reflect.ValueOf(c).Type().String())}
 	}
 
 	mv, op, err := parseWhereArgs(args...)
 	if err != nil {
-		return nil, err
+		return nil, []error{err}
 	}
 
 	ctxv := reflect.ValueOf(ctx)
@@ -47,18 +693,242 @@ func (ns *Namespace) Where(ctx context.Context, c, key any, args ...any) (any, e
 
 	switch seqv.Kind() {
 	case reflect.Array, reflect.Slice:
-		return ns.checkWhereArray(ctxv, seqv, kv, mv, path, op)
+		return ns.checkWhereArrayLenient(ctxv, seqv, kv, mv, path, op)
+	default:
+		return nil, []error{fmt.Errorf("can't iterate over %T", c)}
+	}
+}
+
+// WhereFunc returns the elements of c for which fn returns true. It is an
+// escape hatch for predicates the built-in Where operators can't express.
+// For a map, the entries whose values match are kept. Errors from fn abort
+// the operation and are returned as is.
+func (ns *Namespace) WhereFunc(c any, fn func(v any) (bool, error)) (any, error) {
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value")
+	}
+
+	switch seqv.Kind() {
+	case reflect.Array, reflect.Slice:
+		rv := reflect.MakeSlice(seqv.Type(), 0, 0)
+		for i := 0; i < seqv.Len(); i++ {
+			ok, err := fn(seqv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				rv = reflect.Append(rv, seqv.Index(i))
+			}
+		}
+		return rv.Interface(), nil
 	case reflect.Map:
-		return ns.checkWhereMap(ctxv, seqv, kv, mv, path, op)
+		rv := reflect.MakeMap(seqv.Type())
+		for _, k := range seqv.MapKeys() {
+			elemv := seqv.MapIndex(k)
+			ok, err := fn(elemv.Interface())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				rv.SetMapIndex(k, elemv)
+			}
+		}
+		return rv.Interface(), nil
 	default:
 		return nil, fmt.Errorf("can't iterate over %T", c)
 	}
 }
 
+// WhereRegexpGroup filters c by applying pattern to the string value of key
+// in each element and comparing the capture group with index groupIndex
+// against match using the standard Where operators. The captured string is
+// cast to the type of match before comparison, so numeric comparisons work
+// for e.g. years embedded in slugs. It is an error if pattern doesn't match
+// an element or the group index is out of range.
+func (ns *Namespace) WhereRegexpGroup(ctx context.Context, c, key any, pattern string, groupIndex int, op string, match any) (any, error) {
+	seqv, isNil := indirect(reflect.ValueOf(c))
+	if isNil {
+		return nil, errors.New("can't iterate over a nil value")
+	}
+	if seqv.Kind() != reflect.Array && seqv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't iterate over %T", c)
+	}
+
+	re, err := hstrings.GetOrCompileRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if groupIndex < 0 || groupIndex > re.NumSubexp() {
+		return nil, fmt.Errorf("group index %d out of range for pattern %q with %d group(s)", groupIndex, pattern, re.NumSubexp())
+	}
+
+	mv := reflect.ValueOf(match)
+
+	ctxv := reflect.ValueOf(ctx)
+
+	var path []string
+	kv := reflect.ValueOf(key)
+	if kv.Kind() == reflect.String {
+		path = strings.Split(strings.Trim(kv.String(), "."), ".")
+	}
+
+	rv := reflect.MakeSlice(seqv.Type(), 0, 0)
+
+	for i := 0; i < seqv.Len(); i++ {
+		rvv := seqv.Index(i)
+		vvv := rvv
+		for _, elemName := range path {
+			vvv, err = evaluateSubElem(ctxv, vvv, elemName)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+
+		s, err := toString(vvv)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: key %q is not a string", i, key)
+		}
+
+		groups := re.FindStringSubmatch(s)
+		if groups == nil {
+			return nil, fmt.Errorf("element %d: pattern %q does not match %q", i, pattern, s)
+		}
+
+		cv, err := castWhereGroupValue(groups[groupIndex], mv)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		ok, err := ns.checkCondition(cv, mv, op)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if ok {
+			rv = reflect.Append(rv, rvv)
+		}
+	}
+	return rv.Interface(), nil
+}
+
+// castWhereGroupValue casts the captured string to the kind of mv so
+// checkCondition can compare them.
+func castWhereGroupValue(captured string, mv reflect.Value) (reflect.Value, error) {
+	for mv.Kind() == reflect.Interface {
+		mv = mv.Elem()
+	}
+	switch {
+	case isNumber(mv.Kind()):
+		f, err := strconv.ParseFloat(captured, 64)
+		if err != nil {
+			return zero, fmt.Errorf("can't cast captured value %q to a number: %w", captured, err)
+		}
+		return reflect.ValueOf(f), nil
+	default:
+		return reflect.ValueOf(captured), nil
+	}
+}
+
+// checkWhereArrayLenient is the lenient counterpart to checkWhereArray.
+// Instead of returning on the first error, it skips the offending element
+// and records an error with the element's index.
+func (ns *Namespace) checkWhereArrayLenient(ctxv, seqv, kv, mv reflect.Value, path []string, op string) (any, []error) {
+	rv := reflect.MakeSlice(seqv.Type(), 0, 0)
+	var errs []error
+
+	for i := 0; i < seqv.Len(); i++ {
+		var vvv reflect.Value
+		rvv := seqv.Index(i)
+
+		if kv.Kind() == reflect.String {
+			if isElemSelfPath(path) {
+				vvv = rvv
+			} else if params, ok := rvv.Interface().(maps.Params); ok {
+				vvv = reflect.ValueOf(params.GetNested(path...))
+			} else {
+				var evalErr error
+				vvv = rvv
+				for j, elemName := range path {
+					vvv, evalErr = evaluateSubElem(ctxv, vvv, elemName)
+					if evalErr != nil {
+						break
+					}
+
+					if j < len(path)-1 && vvv.IsValid() {
+						if params, ok := vvv.Interface().(maps.Params); ok {
+							// The current path element is the map itself, .Params.
+							vvv = reflect.ValueOf(params.GetNested(path[j+1:]...))
+							break
+						}
+					}
+				}
+				if evalErr != nil {
+					errs = append(errs, fmt.Errorf("element %d: key %q: %w", i, strings.Join(path, "."), evalErr))
+					continue
+				}
+			}
+		} else {
+			vv, _ := indirect(rvv)
+			if vv.Kind() == reflect.Map && kv.Type().AssignableTo(vv.Type().Key()) {
+				vvv = vv.MapIndex(kv)
+			}
+		}
+
+		ok, err := ns.checkCondition(vvv, mv, op)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("element %d: %w", i, err))
+			continue
+		}
+		if ok {
+			rv = reflect.Append(rv, rvv)
+		}
+	}
+	return rv.Interface(), errs
+}
+
 func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error) {
 	v, vIsNil := indirect(v)
 	if !v.IsValid() {
+		// E.g. an unresolved path; compares equal to nil, like a missing
+		// map key.
 		vIsNil = true
+	} else {
+		// indirect only dereferences pointers and interfaces; a genuinely
+		// nil value of any other nilable kind should also compare equal to
+		// nil.
+		switch v.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+			vIsNil = vIsNil || v.IsNil()
+		}
+	}
+
+	// The "iskind" operator matches on the resolved value's reflect.Kind
+	// rather than its value, which helps filter heterogeneous data (e.g.
+	// messy imported JSON) by type before further processing.
+	if op == "iskind" {
+		kmv, kmvIsNil := indirect(mv)
+		if kmvIsNil || kmv.Kind() != reflect.String {
+			return false, errors.New("iskind: the match value must be a kind name string")
+		}
+		matches, found := kindNamePredicates[strings.ToLower(kmv.String())]
+		if !found {
+			return false, fmt.Errorf("iskind: unknown kind name %q", kmv.String())
+		}
+		if vIsNil {
+			return false, nil
+		}
+		return matches(v.Kind()), nil
+	}
+
+	// The "empty"/"not empty" operators ignore the match value and test
+	// whether v is the zero value for its kind (nil, "", 0, false, or an
+	// empty slice/map). This is different from comparing against nil, which
+	// only matches keys that are absent or explicitly set to nil.
+	switch op {
+	case "empty":
+		return vIsNil || !hreflect.IsTruthfulValue(v), nil
+	case "not empty":
+		return !vIsNil && hreflect.IsTruthfulValue(v), nil
 	}
 
 	mv, mvIsNil := indirect(mv)
@@ -75,6 +945,55 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 		return false, nil
 	}
 
+	// The "csv-contains" operator splits a string field on a delimiter,
+	// trims whitespace and tests membership, which allows filtering on
+	// legacy fields that hold a comma separated string instead of a list.
+	// The default comma delimiter can be overridden with a suffix, e.g.
+	// "csv-contains:;".
+	if strings.HasPrefix(op, "csv-contains") {
+		delimiter := ","
+		if rest := strings.TrimPrefix(op, "csv-contains"); rest != "" {
+			if !strings.HasPrefix(rest, ":") || len(rest) == 1 {
+				return false, errors.New("no such operator")
+			}
+			delimiter = rest[1:]
+		}
+		if v.Kind() != reflect.String {
+			return false, fmt.Errorf("csv-contains: the field must be a string, got %s", v.Kind())
+		}
+		if mv.Kind() != reflect.String {
+			return false, errors.New("csv-contains: the match value must be a string")
+		}
+		for _, field := range strings.Split(v.String(), delimiter) {
+			if strings.TrimSpace(field) == mv.String() {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	// Ordered custom types, e.g. hugo.Version, can participate in the
+	// ordering operators if they implement compare.Comparer. Note that
+	// Compare returns -1 if the other value is less than the receiver.
+	switch op {
+	case "<", "lt", "<=", "le", ">", "gt", ">=", "ge":
+		if v.CanInterface() && mv.CanInterface() {
+			if cmp, ok := v.Interface().(compare.Comparer); ok {
+				r := cmp.Compare(mv.Interface())
+				switch op {
+				case "<", "lt":
+					return r > 0, nil
+				case "<=", "le":
+					return r >= 0, nil
+				case ">", "gt":
+					return r < 0, nil
+				case ">=", "ge":
+					return r <= 0, nil
+				}
+			}
+		}
+	}
+
 	if v.Kind() == reflect.Bool && mv.Kind() == reflect.Bool {
 		switch op {
 		case "", "=", "==", "eq":
@@ -101,9 +1020,9 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 			imv := mv.Int()
 			imvp = &imv
 		case reflect.String:
-			sv := v.String()
+			sv := ns.normString(v.String())
 			svp = &sv
-			smv := mv.String()
+			smv := ns.normString(mv.String())
 			smvp = &smv
 		case reflect.Float64:
 			fv := v.Float()
@@ -154,11 +1073,11 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 				}
 			}
 		case reflect.String:
-			sv := v.String()
+			sv := ns.normString(v.String())
 			svp = &sv
 			for i := 0; i < mv.Len(); i++ {
 				if aString, err := toString(mv.Index(i)); err == nil {
-					sma = append(sma, aString)
+					sma = append(sma, ns.normString(aString))
 				}
 			}
 		case reflect.Float64:
@@ -207,7 +1126,7 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 		case ivp != nil && imvp != nil:
 			return *ivp >= *imvp, nil
 		case svp != nil && smvp != nil:
-			return *svp >= *smvp, nil
+			return ns.compareStrings(*svp, *smvp) >= 0, nil
 		case fvp != nil && fmvp != nil:
 			return *fvp >= *fmvp, nil
 		}
@@ -216,7 +1135,7 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 		case ivp != nil && imvp != nil:
 			return *ivp > *imvp, nil
 		case svp != nil && smvp != nil:
-			return *svp > *smvp, nil
+			return ns.compareStrings(*svp, *smvp) > 0, nil
 		case fvp != nil && fmvp != nil:
 			return *fvp > *fmvp, nil
 		}
@@ -225,7 +1144,7 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 		case ivp != nil && imvp != nil:
 			return *ivp <= *imvp, nil
 		case svp != nil && smvp != nil:
-			return *svp <= *smvp, nil
+			return ns.compareStrings(*svp, *smvp) <= 0, nil
 		case fvp != nil && fmvp != nil:
 			return *fvp <= *fmvp, nil
 		}
@@ -234,11 +1153,23 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 		case ivp != nil && imvp != nil:
 			return *ivp < *imvp, nil
 		case svp != nil && smvp != nil:
-			return *svp < *smvp, nil
+			return ns.compareStrings(*svp, *smvp) < 0, nil
 		case fvp != nil && fmvp != nil:
 			return *fvp < *fmvp, nil
 		}
-	case "in", "not in":
+	case "approx":
+		// Tolerant float equality, e.g. for prices that have been through
+		// arithmetic. Ints compare exactly.
+		switch {
+		case fvp != nil && fmvp != nil:
+			return math.Abs(*fvp-*fmvp) <= whereFloatEpsilon, nil
+		case ivp != nil && imvp != nil:
+			return *ivp == *imvp, nil
+		}
+	case "in", "not in", "ini", "not ini":
+		// The "ini"/"not ini" variants fold case for string membership;
+		// numeric and time values compare exactly with either spelling.
+		fold := strings.HasSuffix(op, "ini")
 		var r bool
 		switch {
 		case ivp != nil && len(ima) > 0:
@@ -246,15 +1177,27 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 		case fvp != nil && len(fma) > 0:
 			r, _ = ns.In(fma, *fvp)
 		case svp != nil:
-			if len(sma) > 0 {
-				r, _ = ns.In(sma, *svp)
+			sv, candidates := *svp, sma
+			if fold {
+				sv = strings.ToLower(sv)
+				candidates = make([]string, len(sma))
+				for i, s := range sma {
+					candidates[i] = strings.ToLower(s)
+				}
+			}
+			if len(candidates) > 0 {
+				r, _ = ns.In(candidates, sv)
 			} else if smvp != nil {
-				r, _ = ns.In(*smvp, *svp)
+				smv := *smvp
+				if fold {
+					smv = strings.ToLower(smv)
+				}
+				r, _ = ns.In(smv, sv)
 			}
 		default:
 			return false, nil
 		}
-		if op == "not in" {
+		if strings.HasPrefix(op, "not ") {
 			return !r, nil
 		}
 		return r, nil
@@ -290,6 +1233,38 @@ func (ns *Namespace) checkCondition(v, mv reflect.Value, op string) (bool, error
 	return false, nil
 }
 
+// paramGetter is the .Param getter convention implemented by e.g. Page.
+// Elements implementing it can be filtered by dynamic keys that aren't
+// fields or methods, e.g. front matter parameters.
+type paramGetter interface {
+	Param(key any) (any, error)
+}
+
+// keyGetter is the simpler Get getter convention, e.g. config providers.
+type keyGetter interface {
+	Get(key string) any
+}
+
+// evaluateGetter resolves elemName through a getter convention, see
+// paramGetter and keyGetter. It reports whether a getter was found; a found
+// getter that returns an error fails the evaluation.
+func evaluateGetter(obj reflect.Value, elemName string) (reflect.Value, bool, error) {
+	if !obj.IsValid() || !obj.CanInterface() {
+		return zero, false, nil
+	}
+	switch g := obj.Interface().(type) {
+	case paramGetter:
+		v, err := g.Param(elemName)
+		if err != nil {
+			return zero, true, fmt.Errorf("error calling Param %q: %w", elemName, err)
+		}
+		return reflect.ValueOf(v), true, nil
+	case keyGetter:
+		return reflect.ValueOf(g.Get(elemName)), true, nil
+	}
+	return zero, false, nil
+}
+
 func evaluateSubElem(ctx, obj reflect.Value, elemName string) (reflect.Value, error) {
 	if !obj.IsValid() {
 		return zero, errors.New("can't evaluate an invalid value")
@@ -354,13 +1329,16 @@ func evaluateSubElem(ctx, obj reflect.Value, elemName string) (reflect.Value, er
 	}
 	switch obj.Kind() {
 	case reflect.Struct:
-		ft, ok := obj.Type().FieldByName(elemName)
+		ft, ok := hreflect.GetStructFieldByName(obj.Type(), elemName)
 		if ok {
 			if ft.PkgPath != "" && !ft.Anonymous {
 				return zero, fmt.Errorf("%s is an unexported field of struct type %s", elemName, typ)
 			}
 			return obj.FieldByIndex(ft.Index), nil
 		}
+		if v, found, err := evaluateGetter(objPtr, elemName); found {
+			return v, err
+		}
 		return zero, fmt.Errorf("%s isn't a field of struct type %s", elemName, typ)
 	case reflect.Map:
 		kv := reflect.ValueOf(elemName)
@@ -369,6 +1347,9 @@ func evaluateSubElem(ctx, obj reflect.Value, elemName string) (reflect.Value, er
 		}
 		return zero, fmt.Errorf("%s isn't a key of map type %s", elemName, typ)
 	}
+	if v, found, err := evaluateGetter(objPtr, elemName); found {
+		return v, err
+	}
 	return zero, fmt.Errorf("%s is neither a struct field, a method nor a map element of type %s", elemName, typ)
 }
 
@@ -395,40 +1376,20 @@ func parseWhereArgs(args ...any) (mv reflect.Value, op string, err error) {
 // checkWhereArray handles the where-matching logic when the seqv value is an
 // Array or Slice.
 func (ns *Namespace) checkWhereArray(ctxv, seqv, kv, mv reflect.Value, path []string, op string) (any, error) {
+	return ns.checkWhereArrayLimit(ctxv, seqv, kv, mv, path, op, -1)
+}
+
+// checkWhereArrayLimit is checkWhereArray with a cap on the number of
+// collected matches; a negative limit means no cap.
+func (ns *Namespace) checkWhereArrayLimit(ctxv, seqv, kv, mv reflect.Value, path []string, op string, limit int) (any, error) {
 	rv := reflect.MakeSlice(seqv.Type(), 0, 0)
 
 	for i := 0; i < seqv.Len(); i++ {
-		var vvv reflect.Value
-		rvv := seqv.Index(i)
-
-		if kv.Kind() == reflect.String {
-			if params, ok := rvv.Interface().(maps.Params); ok {
-				vvv = reflect.ValueOf(params.GetNested(path...))
-			} else {
-				vvv = rvv
-				for i, elemName := range path {
-					var err error
-					vvv, err = evaluateSubElem(ctxv, vvv, elemName)
-
-					if err != nil {
-						continue
-					}
-
-					if i < len(path)-1 && vvv.IsValid() {
-						if params, ok := vvv.Interface().(maps.Params); ok {
-							// The current path element is the map itself, .Params.
-							vvv = reflect.ValueOf(params.GetNested(path[i+1:]...))
-							break
-						}
-					}
-				}
-			}
-		} else {
-			vv, _ := indirect(rvv)
-			if vv.Kind() == reflect.Map && kv.Type().AssignableTo(vv.Type().Key()) {
-				vvv = vv.MapIndex(kv)
-			}
+		if limit >= 0 && rv.Len() >= limit {
+			break
 		}
+		rvv := seqv.Index(i)
+		vvv := whereElemValue(ctxv, rvv, kv, path)
 
 		if ok, err := ns.checkCondition(vvv, mv, op); ok {
 			rv = reflect.Append(rv, rvv)
@@ -483,6 +1444,48 @@ func (ns *Namespace) checkWhereMap(ctxv, seqv, kv, mv reflect.Value, path []stri
 	return rv.Interface(), nil
 }
 
+// countWhereArray counts the elements of seqv that satisfy the condition,
+// matching exactly as checkWhereArray does.
+func (ns *Namespace) countWhereArray(ctxv, seqv, kv, mv reflect.Value, path []string, op string) (int, error) {
+	n := 0
+	for i := 0; i < seqv.Len(); i++ {
+		vvv := whereElemValue(ctxv, seqv.Index(i), kv, path)
+
+		if ok, err := ns.checkCondition(vvv, mv, op); ok {
+			n++
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// countWhereMap counts the map entries that checkWhereMap would keep.
+func (ns *Namespace) countWhereMap(ctxv, seqv, kv, mv reflect.Value, path []string, op string) (int, error) {
+	n := 0
+	for _, k := range seqv.MapKeys() {
+		elemv := seqv.MapIndex(k)
+		if elemv.Kind() == reflect.Interface {
+			var isNil bool
+			elemv, isNil = indirect(elemv)
+			if isNil {
+				continue
+			}
+		}
+		switch elemv.Kind() {
+		case reflect.Array, reflect.Slice:
+			c, err := ns.countWhereArray(ctxv, elemv, kv, mv, path, op)
+			if err != nil {
+				return 0, err
+			}
+			if c > 0 {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
 // toFloat returns the float value if possible.
 func toFloat(v reflect.Value) (float64, error) {
 	switch v.Kind() {
@@ -20,6 +20,7 @@ import (
 	"math/rand"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -902,3 +903,1199 @@ func BenchmarkWhereOps(b *testing.B) {
 		}
 	})
 }
+
+func TestWhereLenient(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []any{
+		map[string]int{"a": 1, "b": 2},
+		"not a map",
+		map[string]int{"a": 3, "b": 4},
+	}
+
+	result, errs := ns.WhereLenient(ctx, seq, "b", 4)
+	if !reflect.DeepEqual(result, []any{map[string]int{"a": 3, "b": 4}}) {
+		t.Errorf("got %v", result)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "element 1") {
+		t.Errorf("got errs %v", errs)
+	}
+
+	// All good, no errors.
+	result, errs = ns.WhereLenient(ctx, []map[string]int{{"a": 1}, {"a": 2}}, "a", "<", 2)
+	if !reflect.DeepEqual(result, []map[string]int{{"a": 1}}) {
+		t.Errorf("got %v", result)
+	}
+	if len(errs) != 0 {
+		t.Errorf("got errs %v", errs)
+	}
+
+	// Not a sequence.
+	_, errs = ns.WhereLenient(ctx, 32, "a", 1)
+	if len(errs) != 1 {
+		t.Errorf("got errs %v", errs)
+	}
+}
+
+func TestWhereRegexpGroup(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []map[string]string{
+		{"slug": "post-2021-03"},
+		{"slug": "post-2023-01"},
+		{"slug": "post-2024-11"},
+	}
+
+	result, err := ns.WhereRegexpGroup(ctx, seq, "slug", `^post-(\d{4})-`, 1, ">=", 2023)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []map[string]string{
+		{"slug": "post-2023-01"},
+		{"slug": "post-2024-11"},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("got %v", result)
+	}
+
+	// String comparison.
+	result, err = ns.WhereRegexpGroup(ctx, seq, "slug", `^post-\d{4}-(\d{2})$`, 1, "eq", "01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []map[string]string{{"slug": "post-2023-01"}}) {
+		t.Errorf("got %v", result)
+	}
+
+	// Pattern not matching an element.
+	_, err = ns.WhereRegexpGroup(ctx, seq, "slug", `^page-(\d+)`, 1, "eq", 1)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("got err %v", err)
+	}
+
+	// Group index out of range.
+	_, err = ns.WhereRegexpGroup(ctx, seq, "slug", `^post-(\d+)`, 2, "eq", 1)
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereFunc(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+
+	result, err := ns.WhereFunc([]int{1, 2, 3, 4}, func(v any) (bool, error) {
+		return v.(int)%2 == 0, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []int{2, 4}) {
+		t.Errorf("got %v", result)
+	}
+
+	result, err = ns.WhereFunc(map[string]int{"a": 1, "b": 2}, func(v any) (bool, error) {
+		return v.(int) > 1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, map[string]int{"b": 2}) {
+		t.Errorf("got %v", result)
+	}
+
+	// Errors from fn propagate.
+	_, err = ns.WhereFunc([]int{1}, func(v any) (bool, error) {
+		return false, fmt.Errorf("boom")
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("got err %v", err)
+	}
+
+	_, err = ns.WhereFunc(32, func(v any) (bool, error) { return true, nil })
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereChannel(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	ch := make(chan map[string]int, 3)
+	ch <- map[string]int{"a": 1}
+	ch <- map[string]int{"a": 2}
+	ch <- map[string]int{"a": 3}
+	close(ch)
+
+	result, err := ns.Where(ctx, ch, "a", ">", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []map[string]int{{"a": 2}, {"a": 3}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("got %v", result)
+	}
+}
+
+type comparableTstVal struct {
+	V int
+}
+
+func (c comparableTstVal) Compare(other any) int {
+	o, ok := other.(comparableTstVal)
+	if !ok {
+		return 0
+	}
+	// Note the reversed semantics of compare.Comparer.
+	return o.V - c.V
+}
+
+func TestWhereOrderedCustomTypes(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	// time.Duration orders by its underlying int64.
+	durations := []map[string]time.Duration{
+		{"d": time.Second},
+		{"d": time.Minute},
+		{"d": time.Hour},
+	}
+	result, err := ns.Where(ctx, durations, "d", ">", 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDurations := []map[string]time.Duration{{"d": time.Minute}, {"d": time.Hour}}
+	if !reflect.DeepEqual(result, expectDurations) {
+		t.Errorf("got %v", result)
+	}
+
+	// Custom types implementing compare.Comparer.
+	vals := []map[string]comparableTstVal{
+		{"v": {V: 1}},
+		{"v": {V: 2}},
+		{"v": {V: 3}},
+	}
+	result, err = ns.Where(ctx, vals, "v", "<=", comparableTstVal{V: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectVals := []map[string]comparableTstVal{{"v": {V: 1}}, {"v": {V: 2}}}
+	if !reflect.DeepEqual(result, expectVals) {
+		t.Errorf("got %v", result)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []map[string]int{{"a": 1}, {"a": 2}, {"a": 3}, {"a": 4}}
+	matched, unmatched, err := ns.Partition(ctx, seq, "a", ">", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectMatched := []map[string]int{{"a": 3}, {"a": 4}}
+	expectUnmatched := []map[string]int{{"a": 1}, {"a": 2}}
+	if !reflect.DeepEqual(matched, expectMatched) {
+		t.Errorf("got matched %v", matched)
+	}
+	if !reflect.DeepEqual(unmatched, expectUnmatched) {
+		t.Errorf("got unmatched %v", unmatched)
+	}
+
+	// No operator.
+	matched, unmatched, err = ns.Partition(ctx, []TstX{{A: "a", B: "b"}, {A: "c", B: "d"}}, "B", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matched, []TstX{{A: "c", B: "d"}}) {
+		t.Errorf("got matched %v", matched)
+	}
+	if !reflect.DeepEqual(unmatched, []TstX{{A: "a", B: "b"}}) {
+		t.Errorf("got unmatched %v", unmatched)
+	}
+
+	// Map input: entries with any matching element go to matched.
+	m := map[string][]map[string]int{
+		"a": {{"b": 1}, {"b": 2}},
+		"c": {{"b": 3}},
+	}
+	matchedm, unmatchedm, err := ns.Partition(ctx, m, "b", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matchedm, map[string][]map[string]int{"c": {{"b": 3}}}) {
+		t.Errorf("got matched %v", matchedm)
+	}
+	if !reflect.DeepEqual(unmatchedm, map[string][]map[string]int{"a": {{"b": 1}, {"b": 2}}}) {
+		t.Errorf("got unmatched %v", unmatchedm)
+	}
+
+	if _, _, err := ns.Partition(ctx, 32, "a", 1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereEmpty(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []map[string]any{
+		{"id": 1, "image": "a.png"},
+		{"id": 2, "image": ""},
+		{"id": 3},
+		{"id": 4, "image": nil},
+	}
+
+	result, err := ns.Where(ctx, seq, "image", "not empty", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []map[string]any{{"id": 1, "image": "a.png"}}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("got %v", result)
+	}
+
+	result, err = ns.Where(ctx, seq, "image", "empty", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 3 {
+		t.Errorf("got %v", result)
+	}
+
+	// Zero numbers and false are empty too.
+	nums := []map[string]int{{"n": 0}, {"n": 1}}
+	result, err = ns.Where(ctx, nums, "n", "empty", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []map[string]int{{"n": 0}}) {
+		t.Errorf("got %v", result)
+	}
+}
+
+func TestWhereAny(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	// A single struct is treated as a one-element sequence.
+	result, err := ns.WhereAny(ctx, TstX{A: "a", B: "b"}, "B", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []TstX{{A: "a", B: "b"}}) {
+		t.Errorf("got %v", result)
+	}
+
+	result, err = ns.WhereAny(ctx, TstX{A: "a", B: "b"}, "B", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 0 {
+		t.Errorf("got %v", result)
+	}
+
+	// Slices are filtered as by Where.
+	result, err = ns.WhereAny(ctx, []TstX{{A: "a", B: "b"}, {A: "c", B: "d"}}, "B", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []TstX{{A: "c", B: "d"}}) {
+		t.Errorf("got %v", result)
+	}
+}
+
+func TestWhereApprox(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	// Note: constant folding would make a literal 0.1+0.2 exactly 0.3,
+	// so compute it at runtime.
+	x, y := 0.1, 0.2
+	seq := []map[string]float64{
+		{"price": x + y}, // 0.30000000000000004
+		{"price": 19.99},
+		{"price": 20.0},
+	}
+
+	result, err := ns.Where(ctx, seq, "price", "approx", 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []map[string]float64{{"price": x + y}}) {
+		t.Errorf("got %v", result)
+	}
+
+	// Exact equality misses the same value.
+	result, err = ns.Where(ctx, seq, "price", "==", 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 0 {
+		t.Errorf("got %v", result)
+	}
+
+	// Mixed int/float compares as floats.
+	result, err = ns.Where(ctx, seq, "price", "approx", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []map[string]float64{{"price": 20.0}}) {
+		t.Errorf("got %v", result)
+	}
+
+	// Ints compare exactly.
+	ints := []map[string]int{{"n": 1}, {"n": 2}}
+	result, err = ns.Where(ctx, ints, "n", "approx", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []map[string]int{{"n": 2}}) {
+		t.Errorf("got %v", result)
+	}
+}
+
+func TestWhereMapValue(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []map[string]any{
+		{"id": 1, "ratings": map[string]int{"editor": 5, "reader": 4}},
+		{"id": 2, "ratings": map[string]int{"editor": 3, "reader": 3}},
+		{"id": 3}, // No ratings; skipped.
+	}
+
+	result, err := ns.WhereMapValue(ctx, seq, "ratings", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rv := reflect.ValueOf(result)
+	if rv.Len() != 1 || rv.Index(0).Interface().(map[string]any)["id"] != 1 {
+		t.Errorf("got %v", result)
+	}
+
+	// With operator.
+	result, err = ns.WhereMapValue(ctx, seq, "ratings", ">=", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 1 {
+		t.Errorf("got %v", result)
+	}
+
+	// Non-map field is an error.
+	if _, err := ns.WhereMapValue(ctx, seq, "id", 1); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereMapValueMatch(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []map[string]any{
+		{"id": 1, "i18n": map[string]string{"en": "Hello", "de": "Hallo"}},
+		{"id": 2, "i18n": map[string]string{"en": "Goodbye", "de": "Tschüss"}},
+		{"id": 3, "mixed": map[string]any{"n": 42, "s": "Hello"}},
+		{"id": 4}, // No map; skipped.
+	}
+
+	result, err := ns.WhereMapValueMatch(ctx, seq, "i18n", "^Hal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rv := reflect.ValueOf(result)
+	if rv.Len() != 1 || rv.Index(0).Interface().(map[string]any)["id"] != 1 {
+		t.Errorf("got %v", result)
+	}
+
+	// Non-string map values are skipped, not an error.
+	result, err = ns.WhereMapValueMatch(ctx, seq, "mixed", "Hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 1 {
+		t.Errorf("got %v", result)
+	}
+
+	// Non-map field is an error.
+	if _, err := ns.WhereMapValueMatch(ctx, seq, "id", "1"); err == nil {
+		t.Error("expected error")
+	}
+
+	// Invalid pattern is an error.
+	if _, err := ns.WhereMapValueMatch(ctx, seq, "i18n", "("); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereIsKind(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	seq := []map[string]any{
+		{"id": 1, "value": "a string"},
+		{"id": 2, "value": 32},
+		{"id": 3, "value": 3.14},
+		{"id": 4, "value": map[string]any{"nested": true}},
+		{"id": 5, "value": []any{1, 2}},
+		{"id": 6},
+	}
+
+	for _, test := range []struct {
+		kind string
+		ids  []int
+	}{
+		{"string", []int{1}},
+		{"int", []int{2}},
+		{"float", []int{3}},
+		{"map", []int{4}},
+		{"slice", []int{5}},
+		{"bool", nil},
+	} {
+		result, err := ns.Where(ctx, seq, "value", "iskind", test.kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int
+		rv := reflect.ValueOf(result)
+		for i := 0; i < rv.Len(); i++ {
+			ids = append(ids, rv.Index(i).Interface().(map[string]any)["id"].(int))
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("[%s] got %v, expected %v", test.kind, ids, test.ids)
+		}
+	}
+
+	// Kind names are case insensitive.
+	result, err := ns.Where(ctx, seq, "value", "iskind", "String")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 1 {
+		t.Errorf("got %v", result)
+	}
+
+	// Unknown kind name is an error.
+	if _, err := ns.Where(ctx, seq, "value", "iskind", "complex"); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := ns.Where(ctx, seq, "value", "iskind", 32); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereNilField(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	thumb := "thumb.jpg"
+	type page struct {
+		Name  string
+		Thumb *string
+		Tags  []string
+	}
+	seq := []page{
+		{Name: "with", Thumb: &thumb, Tags: []string{"a"}},
+		{Name: "without"},
+	}
+
+	for _, test := range []struct {
+		key      string
+		op       string
+		expected []string
+	}{
+		// A nil pointer field compares equal to nil ...
+		{"Thumb", "==", []string{"without"}},
+		// ... and a non-nil one doesn't.
+		{"Thumb", "!=", []string{"with"}},
+		// Same for other nilable kinds, here a nil slice.
+		{"Tags", "==", []string{"without"}},
+		{"Tags", "!=", []string{"with"}},
+	} {
+		result, err := ns.Where(ctx, seq, test.key, test.op, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, p := range result.([]page) {
+			names = append(names, p.Name)
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("[%s %s nil] got %v, expected %v", test.key, test.op, names, test.expected)
+		}
+	}
+
+	// An unresolved path through a nil pointer is not an error; it compares
+	// equal to nil, like a missing map key.
+	result, err := ns.Where(ctx, seq[1:], "Thumb.X", "==", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 1 {
+		t.Errorf("[Thumb.X == nil] got %v", result)
+	}
+	result, err = ns.Where(ctx, seq[1:], "Thumb.X", "==", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 0 {
+		t.Errorf("[Thumb.X == x] got %v", result)
+	}
+}
+
+func TestWhereInBy(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	type author struct {
+		ID   string
+		Name string
+	}
+	authors := []author{
+		{ID: "jane", Name: "Jane Doe"},
+		{ID: "john", Name: "John Doe"},
+	}
+	pages := []map[string]string{
+		{"title": "A", "author": "jane"},
+		{"title": "B", "author": "nn"},
+		{"title": "C", "author": "john"},
+	}
+
+	result, err := ns.WhereInBy(ctx, pages, "author", authors, "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, p := range result.([]map[string]string) {
+		titles = append(titles, p["title"])
+	}
+	if !reflect.DeepEqual(titles, []string{"A", "C"}) {
+		t.Errorf("got %v", titles)
+	}
+
+	// An unresolvable match key is an error.
+	if _, err := ns.WhereInBy(ctx, pages, "author", authors, "Nope"); err == nil {
+		t.Error("expected error")
+	}
+	if _, err := ns.WhereInBy(ctx, pages, "author", nil, "ID"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereCollated(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	cities := []map[string]string{
+		{"name": "Arnsberg"},
+		{"name": "Ärzen"},
+		{"name": "Zürich"},
+	}
+
+	names := func(result any) []string {
+		var names []string
+		for _, c := range result.([]map[string]string) {
+			names = append(names, c["name"])
+		}
+		return names
+	}
+
+	// Code-point ordering sorts Ärzen after Zürich.
+	result, err := ns.Where(ctx, cities, "name", "<", "Bonn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(result); !reflect.DeepEqual(got, []string{"Arnsberg"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// German collation sorts Ärzen with the As.
+	result, err = ns.WhereCollated(ctx, cities, "name", "<", "Bonn", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(result); !reflect.DeepEqual(got, []string{"Arnsberg", "Ärzen"}) {
+		t.Errorf("got %v", got)
+	}
+
+	if _, err := ns.WhereCollated(ctx, cities, "name", "<", "Bonn", "not a tag"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereElementItself(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	result, err := ns.Where(ctx, []string{"go", "hugo", "web"}, ".", "in", []string{"go", "hugo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []string{"go", "hugo"}) {
+		t.Errorf("got %v", result)
+	}
+
+	// The empty key works too.
+	result, err = ns.Where(ctx, []int{1, 2, 3, 4}, "", ">=", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []int{3, 4}) {
+		t.Errorf("got %v", result)
+	}
+
+	d1 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	d3 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err = ns.Where(ctx, []time.Time{d1, d2, d3}, ".", ">", d1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, []time.Time{d2, d3}) {
+		t.Errorf("got %v", result)
+	}
+}
+
+func TestWhereCSVContains(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	pages := []map[string]any{
+		{"title": "A", "tagstring": "go, hugo,web"},
+		{"title": "B", "tagstring": "python; rust"},
+		{"title": "C", "tagstring": "golang"},
+		{"title": "D"},
+	}
+
+	titles := func(result any) []string {
+		var titles []string
+		for _, p := range result.([]map[string]any) {
+			titles = append(titles, p["title"].(string))
+		}
+		return titles
+	}
+
+	result, err := ns.Where(ctx, pages, "tagstring", "csv-contains", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"A"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// The delimiter can be overridden.
+	result, err = ns.Where(ctx, pages, "tagstring", "csv-contains:;", "rust")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"B"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// A non-string field is an error.
+	if _, err := ns.Where(ctx, []map[string]any{{"n": 32}}, "n", "csv-contains", "go"); err == nil {
+		t.Error("expected error")
+	}
+	// A non-string match value is an error.
+	if _, err := ns.Where(ctx, pages, "tagstring", "csv-contains", 32); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWhereCaseInsensitiveIn(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	pages := []map[string]any{
+		{"title": "A", "tag": "go"},
+		{"title": "B", "tag": "Rust"},
+		{"title": "C", "tag": "python"},
+	}
+
+	titles := func(result any) []string {
+		var titles []string
+		for _, p := range result.([]map[string]any) {
+			titles = append(titles, p["title"].(string))
+		}
+		return titles
+	}
+
+	// "in" is exact; "ini" folds case on both sides.
+	result, err := ns.Where(ctx, pages, "tag", "in", []any{"Go", "RUST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); got != nil {
+		t.Errorf("got %v", got)
+	}
+
+	result, err = ns.Where(ctx, pages, "tag", "ini", []any{"Go", "RUST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"A", "B"}) {
+		t.Errorf("got %v", got)
+	}
+
+	result, err = ns.Where(ctx, pages, "tag", "not ini", []string{"Go", "RUST"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"C"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// Numeric membership stays exact with either spelling.
+	nums := []map[string]any{{"title": "A", "n": 1}, {"title": "B", "n": 2}}
+	result, err = ns.Where(ctx, nums, "n", "ini", []int{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"B"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func BenchmarkWhereStructField(b *testing.B) {
+	ns := newNs()
+	ctx := context.Background()
+
+	type page struct {
+		Title   string
+		Section string
+	}
+	seq := make([]page, 50000)
+	for i := range seq {
+		seq[i] = page{Title: "p", Section: "blog"}
+		if i%2 == 0 {
+			seq[i].Section = "docs"
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result, err := ns.Where(ctx, seq, "Section", "eq", "docs")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(result.([]page)) != 25000 {
+			b.Fatal("unexpected result")
+		}
+	}
+}
+
+func TestWhereRangeable(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	// A sync.Map filters like the plain map the caller would otherwise
+	// have copied it into: entries whose slice has matching elements are
+	// kept.
+	var sm sync.Map
+	sm.Store("blog", []any{
+		map[string]any{"title": "A", "draft": true},
+		map[string]any{"title": "B", "draft": false},
+	})
+	sm.Store("docs", []any{
+		map[string]any{"title": "C", "draft": true},
+	})
+
+	result, err := ns.Where(ctx, &sm, "draft", "eq", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := result.(map[any]any)
+	if !ok {
+		t.Fatalf("got %T", result)
+	}
+	if len(m) != 1 {
+		t.Errorf("got %v", m)
+	}
+	if _, found := m["blog"]; !found {
+		t.Errorf("got %v", m)
+	}
+}
+
+func TestCountWhere(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	pages := []map[string]any{
+		{"title": "A", "draft": true},
+		{"title": "B", "draft": false},
+		{"title": "C", "draft": true},
+	}
+
+	n, err := ns.CountWhere(ctx, pages, "draft", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %d", n)
+	}
+
+	n, err = ns.CountWhere(ctx, pages, "draft", "ne", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("got %d", n)
+	}
+
+	// Maps count the entries that Where would keep.
+	taxonomy := map[string][]map[string]any{
+		"blog": {{"title": "A", "draft": true}},
+		"docs": {{"title": "B", "draft": false}},
+	}
+	n, err = ns.CountWhere(ctx, taxonomy, "draft", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("got %d", n)
+	}
+
+	if _, err := ns.CountWhere(ctx, 32, "draft", true); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func BenchmarkCountWhere(b *testing.B) {
+	ns := newNs()
+	ctx := context.Background()
+
+	type page struct {
+		Section string
+	}
+	seq := make([]page, 10000)
+	for i := range seq {
+		seq[i] = page{Section: "blog"}
+		if i%2 == 0 {
+			seq[i].Section = "docs"
+		}
+	}
+
+	b.Run("Where", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ns.Where(ctx, seq, "Section", "eq", "docs"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("CountWhere", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n, err := ns.CountWhere(ctx, seq, "Section", "eq", "docs")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if n != 5000 {
+				b.Fatal("unexpected count")
+			}
+		}
+	})
+}
+
+func TestWhereNormalized(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	// "é" composed (U+00E9) vs decomposed (e + U+0301).
+	composed := "Caf\u00e9"
+	decomposed := "Cafe\u0301"
+
+	pages := []map[string]any{
+		{"title": composed},
+		{"title": "Bar"},
+	}
+
+	result, err := ns.Where(ctx, pages, "title", decomposed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.([]map[string]any); len(got) != 0 {
+		t.Errorf("got %v", got)
+	}
+
+	result, err = ns.WhereNormalized(ctx, pages, "title", decomposed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.([]map[string]any); len(got) != 1 || got[0]["title"] != composed {
+		t.Errorf("got %v", got)
+	}
+
+	// Also for slice membership.
+	result, err = ns.WhereNormalized(ctx, pages, "title", "in", []string{decomposed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.([]map[string]any); len(got) != 1 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestWhereLimit(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	pages := []map[string]any{
+		{"title": "A", "section": "blog"},
+		{"title": "B", "section": "docs"},
+		{"title": "C", "section": "blog"},
+		{"title": "D", "section": "blog"},
+	}
+
+	result, err := ns.WhereLimit(ctx, pages, 2, "section", "blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := result.([]map[string]any)
+	if len(got) != 2 || got[0]["title"] != "A" || got[1]["title"] != "C" {
+		t.Errorf("got %v", got)
+	}
+
+	// A negative limit behaves like Where.
+	result, err = ns.WhereLimit(ctx, pages, -1, "section", "blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.([]map[string]any); len(got) != 3 {
+		t.Errorf("got %v", got)
+	}
+
+	result, err = ns.WhereLimit(ctx, pages, 0, "section", "blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.([]map[string]any); len(got) != 0 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func BenchmarkWhereLimit(b *testing.B) {
+	ns := newNs()
+	ctx := context.Background()
+
+	// All elements match, so a limit of 5 stops after 5 elements.
+	seq := make([]map[string]string, 100000)
+	for i := range seq {
+		seq[i] = map[string]string{"section": "blog"}
+	}
+
+	b.Run("Where", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := ns.Where(ctx, seq, "section", "blog"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("WhereLimit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			result, err := ns.WhereLimit(ctx, seq, 5, "section", "blog")
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(result.([]map[string]string)) != 5 {
+				b.Fatal("unexpected result")
+			}
+		}
+	})
+}
+
+type testParamElem struct {
+	Title  string
+	params map[string]any
+}
+
+func (e testParamElem) Param(key any) (any, error) {
+	if key == "boom" {
+		return nil, fmt.Errorf("no param for %v", key)
+	}
+	return e.params[key.(string)], nil
+}
+
+type testGetElem struct {
+	values map[string]any
+}
+
+func (e testGetElem) Get(key string) any { return e.values[key] }
+
+func TestWhereGetter(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	pages := []testParamElem{
+		{Title: "First", params: map[string]any{"author": "jane"}},
+		{Title: "Second", params: map[string]any{"author": "john"}},
+	}
+
+	// Keys that aren't fields or methods resolve through .Param.
+	result, err := ns.Where(ctx, pages, "author", "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []testParamElem{pages[0]}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("got %v expected %v", result, expect)
+	}
+
+	// Fields still take precedence over the getter.
+	result, err = ns.Where(ctx, pages, "Title", "Second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = []testParamElem{pages[1]}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("got %v expected %v", result, expect)
+	}
+
+	// A getter error is graceful: Where skips the element, WhereLenient
+	// reports it.
+	result, err = ns.Where(ctx, pages, "boom", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 0 {
+		t.Errorf("got %v", result)
+	}
+	_, errs := ns.WhereLenient(ctx, pages, "boom", "x")
+	if len(errs) != 2 {
+		t.Errorf("got %v", errs)
+	}
+
+	// The simpler Get convention.
+	values := []testGetElem{
+		{values: map[string]any{"color": "red"}},
+		{values: map[string]any{"color": "blue"}},
+	}
+	result, err = ns.Where(ctx, values, "color", "blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rv := reflect.ValueOf(result); rv.Len() != 1 || !reflect.DeepEqual(rv.Index(0).Interface(), values[1]) {
+		t.Errorf("got %v", result)
+	}
+}
+
+func TestWhereSorted(t *testing.T) {
+	t.Parallel()
+
+	ns := newNs()
+	ctx := context.Background()
+
+	d1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d3 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	seq := []map[string]any{
+		{"section": "blog", "title": "Charlie", "weight": 30, "date": d1},
+		{"section": "docs", "title": "Delta", "weight": 5, "date": d3},
+		{"section": "blog", "title": "Alpha", "weight": 10, "date": d3},
+		{"section": "blog", "title": "Bravo", "weight": 20, "date": d2},
+	}
+
+	titles := func(result any) []string {
+		rv := reflect.ValueOf(result)
+		var ts []string
+		for i := 0; i < rv.Len(); i++ {
+			ts = append(ts, rv.Index(i).Interface().(map[string]any)["title"].(string))
+		}
+		return ts
+	}
+
+	// Numeric sort key.
+	result, err := ns.WhereSorted(ctx, seq, "section", "blog", "weight", "asc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"Alpha", "Bravo", "Charlie"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// String sort key, descending, with an operator.
+	result, err = ns.WhereSorted(ctx, seq, "section", "!=", "docs", "title", "desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"Charlie", "Bravo", "Alpha"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// Time sort key.
+	result, err = ns.WhereSorted(ctx, seq, "section", "blog", "date", "desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(result); !reflect.DeepEqual(got, []string{"Alpha", "Bravo", "Charlie"}) {
+		t.Errorf("got %v", got)
+	}
+
+	// Invalid sort order.
+	if _, err := ns.WhereSorted(ctx, seq, "section", "blog", "weight", "sideways"); err == nil {
+		t.Error("expected error")
+	}
+
+	// Unresolvable sort key.
+	if _, err := ns.WhereSorted(ctx, seq, "section", "blog", "nosuchkey", "asc"); err == nil {
+		t.Error("expected error")
+	}
+
+	// Too few arguments.
+	if _, err := ns.WhereSorted(ctx, seq, "section", "blog"); err == nil {
+		t.Error("expected error")
+	}
+}
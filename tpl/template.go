@@ -191,14 +191,31 @@ const hugoNewLinePlaceholder = "___hugonl_"
 
 var stripHTMLReplacerPre = strings.NewReplacer("\n", " ", "</p>", hugoNewLinePlaceholder, "<br>", hugoNewLinePlaceholder, "<br />", hugoNewLinePlaceholder)
 
-// StripHTML strips out all HTML tags in s.
+// StripHTMLNewLinePlaceholder is what a replacer passed to StripHTMLWith
+// should emit for markup that is to become a newline in the stripped output.
+const StripHTMLNewLinePlaceholder = hugoNewLinePlaceholder
+
+// StripHTML strips out all HTML tags in s, turning </p>, <br> and <br />
+// into newlines.
 func StripHTML(s string) string {
+	return StripHTMLWith(s, stripHTMLReplacerPre)
+}
+
+// StripHTMLWith is like StripHTML, but with a caller-provided replacer for
+// the pre-processing step. The replacer runs over the raw input before any
+// tags are stripped, so its patterns see the original markup, and any tags
+// it leaves in place are removed afterwards. Replacements meant to end up
+// as newlines should emit StripHTMLNewLinePlaceholder; it is turned into a
+// newline after the tags are stripped, but before runs of consecutive
+// whitespace collapse to a single character, so adjacent newlines still
+// fold into one.
+func StripHTMLWith(s string, repl *strings.Replacer) string {
 	// Shortcut strings with no tags in them
 	if !strings.ContainsAny(s, "<>") {
 		return s
 	}
 
-	pre := stripHTMLReplacerPre.Replace(s)
+	pre := repl.Replace(s)
 	preReplaced := pre != s
 
 	s = htmltemplate.StripTags(pre)
@@ -224,3 +241,59 @@ func StripHTML(s string) string {
 
 	return s
 }
+
+// StripHTMLPreserveNewlines is like StripHTML, but a whitespace run that
+// holds a newline collapses to a newline instead of to its first character,
+// so the line breaks derived from block-level markup (</p>, <br> and
+// <br />) survive, e.g. for plain-text email bodies. Runs of horizontal
+// whitespace still collapse to a single space.
+func StripHTMLPreserveNewlines(s string) string {
+	// Shortcut strings with no tags in them
+	if !strings.ContainsAny(s, "<>") {
+		return s
+	}
+
+	pre := stripHTMLReplacerPre.Replace(s)
+	preReplaced := pre != s
+
+	s = htmltemplate.StripTags(pre)
+
+	if preReplaced {
+		s = strings.ReplaceAll(s, hugoNewLinePlaceholder, "\n")
+	}
+
+	var (
+		wasSpace   bool
+		hasNewline bool
+	)
+	b := bp.GetBuffer()
+	defer bp.PutBuffer(b)
+	writeSep := func() {
+		if hasNewline {
+			b.WriteRune('\n')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			wasSpace = true
+			hasNewline = hasNewline || r == '\n'
+			continue
+		}
+		if wasSpace {
+			writeSep()
+			wasSpace, hasNewline = false, false
+		}
+		b.WriteRune(r)
+	}
+	if wasSpace {
+		writeSep()
+	}
+
+	if b.Len() > 0 {
+		s = b.String()
+	}
+
+	return s
+}
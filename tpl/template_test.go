@@ -14,6 +14,7 @@
 package tpl
 
 import (
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -67,3 +68,51 @@ More text here.</p>
 		}
 	}
 }
+
+func TestStripHTMLPreserveNewlines(t *testing.T) {
+	type test struct {
+		input, expected string
+	}
+	data := []test{
+		{"<p>First para.</p>\n<p>Second para.</p>", "First para.\nSecond para.\n"},
+		{"Line one <br>Line two", "Line one\nLine two"},
+		// Whitespace with no line break still collapses to one space.
+		{"This <strong>is</strong> a\nnewline", "This is a newline"},
+		{"No Tags", "No Tags"},
+		{`<p>One.
+Two.</p>
+
+<p>Three.</p>`, "One. Two.\nThree.\n"},
+	}
+	for i, d := range data {
+		output := StripHTMLPreserveNewlines(d.input)
+		if d.expected != output {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, output)
+		}
+	}
+}
+
+func TestStripHTMLWith(t *testing.T) {
+	repl := strings.NewReplacer(
+		"\n", " ",
+		"</p>", StripHTMLNewLinePlaceholder,
+		"</li>", StripHTMLNewLinePlaceholder,
+	)
+
+	type test struct {
+		input, expected string
+	}
+	data := []test{
+		{"<ul><li>One</li><li>Two</li></ul>", "One\nTwo\n"},
+		{"<p>A paragraph</p>", "A paragraph\n"},
+		// <br> is not in this replacer, so it is just stripped.
+		{"strip br<br>", "strip br"},
+		{"No Tags", "No Tags"},
+	}
+	for i, d := range data {
+		output := StripHTMLWith(d.input, repl)
+		if d.expected != output {
+			t.Errorf("Test %d failed. Expected %q got %q", i, d.expected, output)
+		}
+	}
+}